@@ -0,0 +1,71 @@
+package taxis99
+
+import "context"
+
+// CompanyAPI is the interface implemented by CompanyService.
+type CompanyAPI interface {
+	Find(ctx context.Context) ([]*Company, error)
+	Get(ctx context.Context, id string) (*Company, error)
+}
+
+// CostCenterAPI is the interface implemented by CostCenterService.
+type CostCenterAPI interface {
+	Find(ctx context.Context, f Filter) ([]*CostCenter, error)
+	Each(ctx context.Context, f Filter, fn func(*CostCenter) error) error
+	Get(ctx context.Context, id int64) (*CostCenter, error)
+	Create(ctx context.Context, newCC CostCenter) (*CostCenter, error)
+	Update(ctx context.Context, cc CostCenter) (*CostCenter, error)
+	Patch(ctx context.Context, id int64, p CostCenterPatch) (*CostCenter, error)
+	Enable(ctx context.Context, id int64) (*CostCenter, error)
+	Disable(ctx context.Context, id int64) (*CostCenter, error)
+	Remove(ctx context.Context, id int64) error
+}
+
+// EmployeeAPI is the interface implemented by EmployeeService.
+type EmployeeAPI interface {
+	Find(ctx context.Context, f Filter) ([]*Employee, error)
+	Each(ctx context.Context, f Filter, fn func(*Employee) error) error
+	Get(ctx context.Context, id int64) (*Employee, error)
+	FindByExternalID(ctx context.Context, extID int64) ([]*Employee, error)
+	Create(ctx context.Context, emp Employee, sendEmail bool) (*Employee, error)
+	Import(ctx context.Context, emps []Employee, opts ImportOptions) *ImportReport
+	ImportFrom(ctx context.Context, emps <-chan Employee, opts ImportOptions) *ImportReport
+	Update(ctx context.Context, emp Employee) (*Employee, error)
+	Patch(ctx context.Context, id int64, p EmployeePatch) (*Employee, error)
+	PatchFields(ctx context.Context, emp Employee, mask ...string) (*Employee, error)
+	Enable(ctx context.Context, id int64) (*Employee, error)
+	Disable(ctx context.Context, id int64) (*Employee, error)
+	Remove(ctx context.Context, id int64) error
+	FindCostCenters(ctx context.Context, empID int64) ([]*CostCenter, error)
+	UpdateCostCenters(ctx context.Context, empID int64, costCenterIDs []int64) ([]int64, error)
+}
+
+// API groups the services so consumers can depend on an
+// interface and substitute the Client in tests.
+type API interface {
+	Companies() CompanyAPI
+	CostCenters() CostCenterAPI
+	Employees() EmployeeAPI
+}
+
+var (
+	_ CompanyAPI    = (*CompanyService)(nil)
+	_ CostCenterAPI = (*CostCenterService)(nil)
+	_ EmployeeAPI   = (*EmployeeService)(nil)
+	_ API           = (*Client)(nil)
+)
+
+// Companies returns the Company service.
+func (c *Client) Companies() CompanyAPI {
+	return c.Company
+}
+
+// CostCenters returns the CostCenter service.
+func (c *Client) CostCenters() CostCenterAPI {
+	return c.CostCenter
+}
+
+// Employees returns the Employee service.
+func (c *Client) Employees() EmployeeAPI {
+	return c.Employee
+}
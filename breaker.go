@@ -0,0 +1,264 @@
+package taxis99
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerWindow   = time.Minute
+	defaultHalfOpenCalls   = 1
+	defaultBreakerMinCalls = 10
+)
+
+// ErrCircuitOpen is returned without calling the API
+// while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("taxis99: circuit open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every request with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a few trial requests through
+	// to check whether the API recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreaker stops sending requests while the API is failing.
+// The circuit opens when the ratio of failed requests in the window
+// reaches the threshold, and half-opens after the cool-down to send
+// trial requests: it closes if they all succeed or opens again if
+// any fails. Only server errors (5xx) and transport failures count
+// as failures; client errors like 422 don't.
+type CircuitBreaker struct {
+	ratio    float64
+	minCalls int
+	coolDown time.Duration
+
+	// Window is the interval over which the failure ratio is
+	// computed while closed. Defaults to 1 minute.
+	Window time.Duration
+
+	// HalfOpenRequests is the number of trial requests sent
+	// while half-open. Defaults to 1.
+	HalfOpenRequests int
+
+	mu        sync.Mutex
+	state     CircuitState
+	gen       uint64
+	requests  int
+	failures  int
+	trials    int
+	successes int
+	since     time.Time
+	callbacks []func(from, to CircuitState)
+	// changes are the state changes whose callbacks
+	// are called once the lock is released.
+	changes []stateChange
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker opening when
+// the ratio of failed requests reaches ratio, once there are at
+// least minRequests in the window. It stays open for coolDown.
+func NewCircuitBreaker(ratio float64, minRequests int, coolDown time.Duration) *CircuitBreaker {
+	if minRequests <= 0 {
+		minRequests = defaultBreakerMinCalls
+	}
+
+	return &CircuitBreaker{
+		ratio:    ratio,
+		minCalls: minRequests,
+		coolDown: coolDown,
+		now:      time.Now,
+	}
+}
+
+// OnStateChange adds a callback called on every state change.
+// Callbacks are called synchronously, after the breaker is unlocked,
+// so they can call State; they must not block.
+func (b *CircuitBreaker) OnStateChange(fn func(from, to CircuitState)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.callbacks = append(b.callbacks, fn)
+}
+
+// State returns the current state.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.refresh(b.now())
+	return b.state
+}
+
+// allow returns ErrCircuitOpen if the request can't be sent.
+// Otherwise it returns the generation of the state the request
+// is sent in, which must be passed to record.
+func (b *CircuitBreaker) allow() (uint64, error) {
+	if b == nil {
+		return 0, nil
+	}
+
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.refresh(b.now())
+
+	switch b.state {
+	case CircuitOpen:
+		return b.gen, ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.trials >= b.halfOpenRequests() {
+			return b.gen, ErrCircuitOpen
+		}
+		b.trials++
+	}
+
+	return b.gen, nil
+}
+
+// record counts the result of a request allowed in the generation.
+// Results of requests sent before a state change are ignored.
+func (b *CircuitBreaker) record(gen uint64, failed bool) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.unlock()
+
+	now := b.now()
+	b.refresh(now)
+
+	if gen != b.gen {
+		return
+	}
+
+	switch b.state {
+	case CircuitClosed:
+		b.requests++
+		if failed {
+			b.failures++
+		}
+		if b.requests >= b.minCalls && float64(b.failures)/float64(b.requests) >= b.ratio {
+			b.setState(CircuitOpen, now)
+		}
+	case CircuitHalfOpen:
+		if failed {
+			b.setState(CircuitOpen, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.halfOpenRequests() {
+			b.setState(CircuitClosed, now)
+		}
+	}
+}
+
+// release gives back the trial of a request allowed
+// in the generation but not sent.
+func (b *CircuitBreaker) release(gen uint64) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if gen == b.gen && b.state == CircuitHalfOpen && b.trials > 0 {
+		b.trials--
+	}
+}
+
+// refresh half-opens the circuit after the cool-down
+// and resets the counts of an expired window.
+func (b *CircuitBreaker) refresh(now time.Time) {
+	switch b.state {
+	case CircuitOpen:
+		if now.Sub(b.since) >= b.coolDown {
+			b.setState(CircuitHalfOpen, now)
+		}
+	case CircuitClosed:
+		if now.Sub(b.since) >= b.window() {
+			b.since = now
+			b.requests, b.failures = 0, 0
+		}
+	}
+}
+
+func (b *CircuitBreaker) setState(s CircuitState, now time.Time) {
+	from := b.state
+
+	b.state = s
+	b.gen++
+	b.since = now
+	b.requests, b.failures = 0, 0
+	b.trials, b.successes = 0, 0
+
+	b.changes = append(b.changes, stateChange{from, s})
+}
+
+type stateChange struct {
+	from, to CircuitState
+}
+
+// unlock releases the lock before calling the callbacks
+// of the state changes made while holding it.
+func (b *CircuitBreaker) unlock() {
+	changes, callbacks := b.changes, b.callbacks
+	b.changes = nil
+	b.mu.Unlock()
+
+	for _, c := range changes {
+		for _, fn := range callbacks {
+			fn(c.from, c.to)
+		}
+	}
+}
+
+func (b *CircuitBreaker) window() time.Duration {
+	if b.Window <= 0 {
+		return defaultBreakerWindow
+	}
+	return b.Window
+}
+
+func (b *CircuitBreaker) halfOpenRequests() int {
+	if b.HalfOpenRequests <= 0 {
+		return defaultHalfOpenCalls
+	}
+	return b.HalfOpenRequests
+}
+
+// apiFailure reports whether the attempt failed because of
+// the API: a transport error or a server error. Requests
+// cancelled by the caller are not failures.
+func apiFailure(ctx context.Context, res *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	return res.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,323 @@
+package taxis99
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestBreaker(ratio float64, min int, coolDown time.Duration) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewCircuitBreaker(ratio, min, coolDown)
+	b.now = clock.now
+	return b, clock
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	b, clock := newTestBreaker(0.5, 4, time.Second)
+
+	var changes []string
+	b.OnStateChange(func(from, to CircuitState) {
+		changes = append(changes, from.String()+" -> "+to.String())
+	})
+
+	send := func(failed bool) error {
+		gen, err := b.allow()
+		if err != nil {
+			return err
+		}
+		b.record(gen, failed)
+		return nil
+	}
+
+	// 1 failure out of 3 requests keeps the circuit closed.
+	send(false)
+	send(true)
+	send(false)
+	if got := b.State(); got != CircuitClosed {
+		t.Fatalf("Got state %s; want closed.", got)
+	}
+
+	// 2 failures out of 4 requests reach the ratio.
+	send(true)
+	if got := b.State(); got != CircuitOpen {
+		t.Fatalf("Got state %s; want open.", got)
+	}
+
+	if err := send(false); err != ErrCircuitOpen {
+		t.Errorf("Got error '%v' while open; want ErrCircuitOpen.", err)
+	}
+
+	clock.t = clock.t.Add(time.Second)
+	if got := b.State(); got != CircuitHalfOpen {
+		t.Fatalf("Got state %s after the cool-down; want half-open.", got)
+	}
+
+	// A single trial request is allowed while half-open.
+	gen, err := b.allow()
+	if err != nil {
+		t.Fatalf("Got unexpected error '%s' for the trial; want nil.", err.Error())
+	}
+	if err := send(false); err != ErrCircuitOpen {
+		t.Errorf("Got error '%v' during the trial; want ErrCircuitOpen.", err)
+	}
+
+	// The failed trial opens the circuit again.
+	b.record(gen, true)
+	if got := b.State(); got != CircuitOpen {
+		t.Fatalf("Got state %s after the failed trial; want open.", got)
+	}
+
+	clock.t = clock.t.Add(time.Second)
+	if err := send(false); err != nil {
+		t.Fatalf("Got unexpected error '%s' for the trial; want nil.", err.Error())
+	}
+	if got := b.State(); got != CircuitClosed {
+		t.Fatalf("Got state %s after the successful trial; want closed.", got)
+	}
+
+	want := []string{
+		"closed -> open",
+		"open -> half-open",
+		"half-open -> open",
+		"open -> half-open",
+		"half-open -> closed",
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Got state changes %v; want %v.", changes, want)
+	}
+}
+
+func TestCircuitBreakerWindow(t *testing.T) {
+	b, clock := newTestBreaker(0.5, 2, time.Second)
+	b.Window = time.Minute
+
+	gen, _ := b.allow()
+	b.record(gen, true)
+
+	// The failure expires with the window.
+	clock.t = clock.t.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		gen, _ := b.allow()
+		b.record(gen, false)
+	}
+	gen, _ = b.allow()
+	b.record(gen, true)
+
+	if got := b.State(); got != CircuitClosed {
+		t.Errorf("Got state %s; want closed.", got)
+	}
+}
+
+func TestCircuitBreakerStaleResult(t *testing.T) {
+	b, clock := newTestBreaker(0.5, 1, time.Second)
+
+	stale, _ := b.allow()
+	gen, _ := b.allow()
+	b.record(gen, true)
+
+	clock.t = clock.t.Add(time.Second)
+	b.State()
+
+	// The result of the request sent while closed
+	// doesn't close the half-open circuit.
+	b.record(stale, false)
+	if got := b.State(); got != CircuitHalfOpen {
+		t.Errorf("Got state %s; want half-open.", got)
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status int
+		want   CircuitState
+	}{
+		{"ServerError", http.StatusServiceUnavailable, CircuitOpen},
+		{"ValidationError", http.StatusUnprocessableEntity, CircuitClosed},
+		{"NotFound", http.StatusNotFound, CircuitClosed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(tc.status)
+			})
+			defer srv.Close()
+
+			client.Breaker, _ = newTestBreaker(0.5, 2, time.Minute)
+
+			for i := 0; i < 2; i++ {
+				client.Company.Find(context.Background())
+			}
+
+			if got := client.Breaker.State(); got != tc.want {
+				t.Fatalf("Got state %s; want %s.", got, tc.want)
+			}
+
+			_, err := client.Company.Find(context.Background())
+			if gotOpen := errors.Is(err, ErrCircuitOpen); gotOpen != (tc.want == CircuitOpen) {
+				t.Errorf("Got error '%v'; want ErrCircuitOpen %t.", err, tc.want == CircuitOpen)
+			}
+
+			wantCalls := 3
+			if tc.want == CircuitOpen {
+				wantCalls = 2
+			}
+			if calls != wantCalls {
+				t.Errorf("Got %d calls to the API; want %d.", calls, wantCalls)
+			}
+		})
+	}
+}
+
+func TestClientCircuitBreakerRetry(t *testing.T) {
+	var calls int
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	defer srv.Close()
+
+	client.Retry = &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}
+	client.Breaker, _ = newTestBreaker(1, 2, time.Minute)
+
+	_, err := client.Company.Find(context.Background())
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Got error '%v'; want ErrCircuitOpen.", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("Got %d calls to the API; want the retries to stop once open after 2.", calls)
+	}
+}
+
+func TestClientCircuitBreakerTransportError(t *testing.T) {
+	var calls int
+	hc := &http.Client{Transport: testRoundTripperFn(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errors.New("Testing error.")
+	})}
+
+	client := NewClient(hc)
+	client.Breaker, _ = newTestBreaker(0.5, 1, time.Minute)
+
+	if _, err := client.Company.Find(context.Background()); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Got error '%v'; want the transport error.", err)
+	}
+
+	if _, err := client.Company.Find(context.Background()); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Got error '%v'; want ErrCircuitOpen.", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Got %d calls to the API; want 1.", calls)
+	}
+}
+
+func TestClientCircuitBreakerCancelled(t *testing.T) {
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {})
+	defer srv.Close()
+
+	client.Breaker, _ = newTestBreaker(0.5, 1, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.Company.Find(ctx)
+
+	if got := client.Breaker.State(); got != CircuitClosed {
+		t.Errorf("Got state %s after a cancelled request; want closed.", got)
+	}
+}
+
+func TestClientCircuitBreakerHalfOpenCancelled(t *testing.T) {
+	var fail bool
+	hc := &http.Client{Transport: testRoundTripperFn(func(r *http.Request) (*http.Response, error) {
+		if fail {
+			return nil, errors.New("Testing error.")
+		}
+		// Answers only once the caller gives up.
+		<-r.Context().Done()
+		return nil, r.Context().Err()
+	})}
+
+	client := NewClient(hc)
+	b, clock := newTestBreaker(0.5, 1, time.Second)
+	client.Breaker = b
+
+	var changes []string
+	b.OnStateChange(func(from, to CircuitState) {
+		changes = append(changes, from.String()+" -> "+to.String())
+	})
+
+	fail = true
+	client.Company.Find(context.Background())
+	fail = false
+
+	clock.t = clock.t.Add(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Company.Find(ctx); errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Got error '%v'; want the trial to be sent.", err)
+	}
+
+	if got := b.State(); got != CircuitHalfOpen {
+		t.Errorf("Got state %s after a cancelled trial; want half-open.", got)
+	}
+
+	want := []string{"closed -> open", "open -> half-open"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Got state changes %v; want %v.", changes, want)
+	}
+
+	// The cancelled trial frees its slot for the next one.
+	if _, err := b.allow(); err != nil {
+		t.Errorf("Got error '%v' after a cancelled trial; want another trial allowed.", err)
+	}
+}
+
+func TestCircuitBreakerCallbackState(t *testing.T) {
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer srv.Close()
+
+	b, clock := newTestBreaker(0.5, 1, time.Second)
+	client.Breaker = b
+
+	var got []CircuitState
+	b.OnStateChange(func(from, to CircuitState) {
+		got = append(got, b.State())
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.Company.Find(context.Background())
+		clock.t = clock.t.Add(time.Second)
+		b.State()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Got the callback calling State blocked; want it to return.")
+	}
+
+	want := []CircuitState{CircuitOpen, CircuitHalfOpen}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got states %v read by the callback; want %v.", got, want)
+	}
+}
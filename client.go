@@ -4,40 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const (
 	defaultBaseURL = "https://api.corp.99taxis.com/v2/"
 )
 
-// ApiError implements the error interface
-// and returns infos from the request.
-type APIError struct {
-	StatusCode int
-	Msg        string
-	Err        error
-}
-
-func (e *APIError) Error() string {
-	return fmt.Sprintf("Error Status Code: %d; Message: %s.", e.StatusCode, e.Msg)
-}
-
-func (e *APIError) Unwrap() error {
-	return e.Err
-}
-
-// unprocessableEntityError is the validation error struct from the API.
-type unprocessableEntityError struct {
-	Code    string `json:"code,omitempty"`
-	Field   string `json:"field,omitempty"`
-	Message string `json:"message,omitempty"`
-}
-
 // requester is the interface that performs a request
 // to the server and delegates the parsing to the parser interface.
 type requester interface {
@@ -57,12 +35,39 @@ type Client struct {
 	// Should always be specified with a trailing slash.
 	BaseURL *url.URL
 
+	// UserAgent is sent in every request if it's not empty.
+	UserAgent string
+
+	// Retry is the policy used to retry failed requests.
+	// Requests are not retried if it's nil.
+	Retry *RetryPolicy
+
+	// RateLimiter limits the requests sent to the API.
+	// Requests are not limited if it's nil.
+	RateLimiter *RateLimiter
+
+	// Breaker fails fast with ErrCircuitOpen while the API is failing.
+	// Requests always go through if it's nil.
+	Breaker *CircuitBreaker
+
+	// IdempotencyKeys generates an idempotency key for the POST
+	// requests without one, making them safe to retry.
+	IdempotencyKeys bool
+
+	// Logger logs every attempt with its status and latency.
+	// Personal data is redacted. Nothing is logged if it's nil.
+	Logger Logger
+
 	// reuse a single struct instead of allocating one for each service on the heap.
 	common service
 
+	middlewares []Middleware
+
 	Company    *CompanyService
 	CostCenter *CostCenterService
 	Employee   *EmployeeService
+	Ride       *RideService
+	Estimate   *EstimateService
 }
 
 // NewClient returns a reference to the Client struct.
@@ -83,6 +88,8 @@ func NewClient(hc *http.Client) *Client {
 	c.Company = (*CompanyService)(&c.common)
 	c.CostCenter = (*CostCenterService)(&c.common)
 	c.Employee = (*EmployeeService)(&c.common)
+	c.Ride = (*RideService)(&c.common)
+	c.Estimate = (*EstimateService)(&c.common)
 
 	return c
 }
@@ -90,51 +97,201 @@ func NewClient(hc *http.Client) *Client {
 // Request created an API request. A relative path can be providaded
 // in which case it is resolved relative to the host of the Client.
 func (c *Client) Request(ctx context.Context, method, path string, body, output interface{}) error {
-	u, err := c.BaseURL.Parse(path)
+	call := &Call{
+		Operation: operation(ctx),
+		Method:    method,
+		Path:      path,
+		Body:      body,
+		Output:    output,
+	}
+
+	if method == http.MethodPost {
+		call.IdempotencyKey = c.requestIdempotencyKey(ctx)
+	}
+
+	return c.chain(c.send)(ctx, call)
+}
+
+// send is the Handler at the end of the middleware chain
+// sending the call to the API.
+func (c *Client) send(ctx context.Context, call *Call) error {
+	u, err := c.BaseURL.Parse(call.Path)
 	if err != nil {
 		return err
 	}
 
+	// The body is encoded once so it can be replayed on retries.
 	var buf bytes.Buffer
-	if body != nil {
-		json.NewEncoder(&buf).Encode(body)
+	if call.Body != nil {
+		if err := json.NewEncoder(&buf).Encode(call.Body); err != nil {
+			return err
+		}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), &buf)
+	var res *http.Response
+	for attempt := 1; ; attempt++ {
+		var gen uint64
+		gen, err = c.Breaker.allow()
+		if err != nil {
+			return err
+		}
+
+		wait := time.Now()
+		if err := c.RateLimiter.Wait(ctx, call.Path); err != nil {
+			c.Breaker.release(gen)
+			return err
+		}
+		call.LimiterWait += time.Since(wait)
+
+		call.Attempts = attempt
+		start := time.Now()
+		res, err = c.do(ctx, call, u.String(), buf.Bytes())
+		if err != nil && ctx.Err() != nil {
+			// Attempts cancelled by the caller say nothing about
+			// the API, so they are neither a failure nor a success.
+			c.Breaker.release(gen)
+		} else {
+			c.Breaker.record(gen, apiFailure(ctx, res, err))
+		}
+		c.log(ctx, call, attempt, time.Since(start), res, err)
+		traceAttempt(ctx, attempt, res, err)
+		if err == nil {
+			call.StatusCode = res.StatusCode
+			if res.StatusCode == http.StatusTooManyRequests {
+				c.RateLimiter.throttled(call.Path)
+			} else if res.StatusCode < http.StatusBadRequest {
+				c.RateLimiter.succeeded(call.Path)
+			}
+		}
+
+		replayable := idempotent(call.Method) || call.IdempotencyKey != ""
+		delay, retry := c.Retry.retry(replayable, attempt, res, err)
+		if !retry {
+			break
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			if res != nil {
+				res.Body.Close()
+			}
+			return err
+		}
+
+		// The create may have been applied before an ambiguous failure,
+		// in which case the entity is returned instead of creating it
+		// again. The attempt error is kept if the lookup fails.
+		if call.Method == http.MethodPost && apiFailure(ctx, res, err) {
+			found, lerr := dedupe(ctx)
+			if lerr != nil {
+				break
+			}
+			if found {
+				if res != nil {
+					res.Body.Close()
+				}
+				return nil
+			}
+		}
+
+		if res != nil {
+			// Drains the body so the connection can be reused.
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+	}
 	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
 
-	if buf.Len() > 0 {
-		req.Header.Add("Content-Type", "application/json")
-	}
+	return decodeResponse(res, call.Output)
+}
 
-	res, err := c.client.Do(req)
+// decodeResponse checks the response status code before
+// decoding the body into output. Responses with status code
+// other than 2xx are returned as *APIError.
+func decodeResponse(res *http.Response, output interface{}) error {
+	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return err
 	}
-	defer res.Body.Close()
 
-	// TODO: find a better way to handle http status code.
-	if status := res.StatusCode; status == http.StatusUnprocessableEntity {
-		var e unprocessableEntityError
-		if err := json.NewDecoder(res.Body).Decode(&e); err != nil {
-			return err
-		}
-		return &APIError{
-			StatusCode: status,
-			Msg:        fmt.Sprintf("taxis99: %s", e.Message),
-		}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return newAPIError(res, body)
 	}
 
-	// Ignores io.EOF error caused by empty response body.
-	if err = json.NewDecoder(res.Body).Decode(output); err != nil && !errors.Is(err, io.EOF) {
+	// Ignores empty response bodies.
+	if output == nil || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(body, output); err != nil {
 		return &APIError{
 			StatusCode: res.StatusCode,
 			Msg:        fmt.Sprintf("taxis99: '%s'.", err.Error()),
 			Err:        err,
+			Body:       body,
+			RequestID:  res.Header.Get(headerRequestID),
 		}
 	}
 
 	return nil
 }
+
+// log logs the attempt, if the Client has a Logger.
+func (c *Client) log(ctx context.Context, call *Call, attempt int, latency time.Duration, res *http.Response, err error) {
+	if c.Logger == nil {
+		return
+	}
+
+	e := LogEntry{
+		Operation: call.Operation,
+		Method:    call.Method,
+		Path:      redactPath(call.Path),
+		Latency:   latency,
+		Attempt:   attempt,
+		CompanyID: c.companyID(ctx),
+		Err:       redactError(err),
+	}
+	if res != nil {
+		e.StatusCode = res.StatusCode
+	}
+
+	c.Logger.Log(ctx, e)
+}
+
+// companyID returns the company of the request, either
+// from the context or from the Client Transport.
+func (c *Client) companyID(ctx context.Context) string {
+	if cid, ok := ctx.Value(CompanyID).(string); ok {
+		return cid
+	}
+	if t, ok := c.client.Transport.(*Transport); ok {
+		return t.CompanyID
+	}
+	return ""
+}
+
+// do sends a single HTTP request with the encoded body.
+func (c *Client) do(ctx context.Context, call *Call, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, call.Method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) > 0 {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	if call.IdempotencyKey != "" {
+		req.Header.Set(headerIdempotencyKey, call.IdempotencyKey)
+	}
+
+	injectTraceContext(ctx, req.Header)
+
+	return c.client.Do(req)
+}
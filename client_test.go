@@ -431,3 +431,171 @@ func testError(t *testing.T, run func(*Client) error) {
 		t.Error("Got error nil; want it not nil.")
 	}
 }
+
+// serviceCalls calls every service method with the given context.
+var serviceCalls = []struct {
+	name string
+	call func(context.Context, *Client) error
+}{
+	{"Company.Find", func(ctx context.Context, c *Client) error {
+		_, err := c.Company.Find(ctx)
+		return err
+	}},
+	{"Company.Get", func(ctx context.Context, c *Client) error {
+		// The mock server has no companies.
+		if _, err := c.Company.Get(ctx, "123"); !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		return nil
+	}},
+	{"CostCenter.Find", func(ctx context.Context, c *Client) error {
+		_, err := c.CostCenter.Find(ctx, nil)
+		return err
+	}},
+	{"CostCenter.Get", func(ctx context.Context, c *Client) error {
+		_, err := c.CostCenter.Get(ctx, 1)
+		return err
+	}},
+	{"CostCenter.Create", func(ctx context.Context, c *Client) error {
+		_, err := c.CostCenter.Create(ctx, CostCenter{Name: "IT"})
+		return err
+	}},
+	{"CostCenter.Update", func(ctx context.Context, c *Client) error {
+		_, err := c.CostCenter.Update(ctx, CostCenter{ID: 1, Name: "IT"})
+		return err
+	}},
+	{"CostCenter.Patch", func(ctx context.Context, c *Client) error {
+		_, err := c.CostCenter.Patch(ctx, 1, CostCenterPatch{})
+		return err
+	}},
+	{"CostCenter.Enable", func(ctx context.Context, c *Client) error {
+		_, err := c.CostCenter.Enable(ctx, 1)
+		return err
+	}},
+	{"CostCenter.Disable", func(ctx context.Context, c *Client) error {
+		_, err := c.CostCenter.Disable(ctx, 1)
+		return err
+	}},
+	{"CostCenter.Remove", func(ctx context.Context, c *Client) error {
+		return c.CostCenter.Remove(ctx, 1)
+	}},
+	{"Employee.Find", func(ctx context.Context, c *Client) error {
+		_, err := c.Employee.Find(ctx, nil)
+		return err
+	}},
+	{"Employee.Get", func(ctx context.Context, c *Client) error {
+		_, err := c.Employee.Get(ctx, 1)
+		return err
+	}},
+	{"Employee.FindByExternalID", func(ctx context.Context, c *Client) error {
+		_, err := c.Employee.FindByExternalID(ctx, 1)
+		return err
+	}},
+	{"Employee.Create", func(ctx context.Context, c *Client) error {
+		_, err := c.Employee.Create(ctx, Employee{Name: "José"}, false)
+		return err
+	}},
+	{"Employee.Update", func(ctx context.Context, c *Client) error {
+		_, err := c.Employee.Update(ctx, Employee{ID: 1})
+		return err
+	}},
+	{"Employee.Patch", func(ctx context.Context, c *Client) error {
+		_, err := c.Employee.Patch(ctx, 1, EmployeePatch{})
+		return err
+	}},
+	{"Employee.PatchFields", func(ctx context.Context, c *Client) error {
+		_, err := c.Employee.PatchFields(ctx, Employee{ID: 1}, "enabled")
+		return err
+	}},
+	{"Employee.Enable", func(ctx context.Context, c *Client) error {
+		_, err := c.Employee.Enable(ctx, 1)
+		return err
+	}},
+	{"Employee.Disable", func(ctx context.Context, c *Client) error {
+		_, err := c.Employee.Disable(ctx, 1)
+		return err
+	}},
+	{"Employee.Remove", func(ctx context.Context, c *Client) error {
+		return c.Employee.Remove(ctx, 1)
+	}},
+	{"Employee.FindCostCenters", func(ctx context.Context, c *Client) error {
+		_, err := c.Employee.FindCostCenters(ctx, 1)
+		return err
+	}},
+	{"Employee.UpdateCostCenters", func(ctx context.Context, c *Client) error {
+		_, err := c.Employee.UpdateCostCenters(ctx, 1, []int64{1})
+		return err
+	}},
+	{"Ride.Find", func(ctx context.Context, c *Client) error {
+		_, err := c.Ride.Find(ctx, nil)
+		return err
+	}},
+	{"Ride.Get", func(ctx context.Context, c *Client) error {
+		_, err := c.Ride.Get(ctx, 1)
+		return err
+	}},
+	{"Ride.Create", func(ctx context.Context, c *Client) error {
+		_, err := c.Ride.Create(ctx, Employee{ID: 1}, RideRequest{})
+		return err
+	}},
+	{"Ride.Cancel", func(ctx context.Context, c *Client) error {
+		return c.Ride.Cancel(ctx, 1, "")
+	}},
+	{"Estimate.Find", func(ctx context.Context, c *Client) error {
+		_, err := c.Estimate.Find(ctx, EstimateRequest{})
+		return err
+	}},
+}
+
+func TestServiceContextCancelled(t *testing.T) {
+	for _, sc := range serviceCalls {
+		t.Run(sc.name, func(t *testing.T) {
+			var called bool
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}
+
+			client, srv := newMockServer(nil, handler)
+			defer srv.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			if err := sc.call(ctx, client); !errors.Is(err, context.Canceled) {
+				t.Errorf("Got error '%v'; want context.Canceled.", err)
+			}
+
+			if called {
+				t.Error("Got request sent to the server; want it not to be sent.")
+			}
+		})
+	}
+}
+
+func TestServiceContextCompanyID(t *testing.T) {
+	for _, sc := range serviceCalls {
+		t.Run(sc.name, func(t *testing.T) {
+			var got string
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get(headerCompanyID)
+			}
+
+			hc := &http.Client{
+				Transport: &Transport{Key: "key", CompanyID: "default"},
+			}
+
+			client, srv := newMockServer(hc, handler)
+			defer srv.Close()
+
+			ctx := context.WithValue(context.Background(), CompanyID, "override")
+
+			if err := sc.call(ctx, client); err != nil {
+				t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+			}
+
+			if got != "override" {
+				t.Errorf("Got %s Header '%s'; want 'override'.", headerCompanyID, got)
+			}
+		})
+	}
+}
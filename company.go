@@ -16,9 +16,28 @@ type CompanyService service
 
 func (c *CompanyService) Find(ctx context.Context) ([]*Company, error) {
 	var companies []*Company
-	err := c.client.Request(ctx, http.MethodGet, string(companiesEndpoint), nil, &companies)
+	err := c.client.Request(withOperation(ctx, "Company.Find"), http.MethodGet, string(companiesEndpoint), nil, &companies)
 	if err != nil {
 		return nil, err
 	}
 	return companies, nil
 }
+
+// Get returns the company with the ID among the ones the API key has
+// access to, as the API has no endpoint for a single company. It returns
+// ErrNotFound if there's none.
+func (c *CompanyService) Get(ctx context.Context, id string) (*Company, error) {
+	var companies []*Company
+	err := c.client.Request(withOperation(ctx, "Company.Get"), http.MethodGet, string(companiesEndpoint), nil, &companies)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, company := range companies {
+		if company.ID == id {
+			return company, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
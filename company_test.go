@@ -2,6 +2,8 @@ package taxis99
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
 )
@@ -30,3 +32,40 @@ func TestCompanyFindError(t *testing.T) {
 		return err
 	})
 }
+
+func TestCompanyGet(t *testing.T) {
+	// The mock server has no companies.
+	get := func(c *Client) error {
+		if _, err := c.Company.Get(context.Background(), "123"); !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	testPath(t, string(companiesEndpoint), get)
+	testMethod(t, http.MethodGet, get)
+
+	request := func(ctx context.Context, method, path string, body, output interface{}) error {
+		return json.Unmarshal([]byte(`[{"id":"123","name":"Mobilitee"},{"id":"456","name":"99"}]`), output)
+	}
+	c := newMockRequesterClient(mockRequester(request))
+
+	got, err := c.Company.Get(context.Background(), "456")
+	if err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+	if want := (Company{ID: "456", Name: "99"}); *got != want {
+		t.Errorf("Got company %+v; want %+v.", *got, want)
+	}
+
+	if _, err := c.Company.Get(context.Background(), "789"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Got error '%v'; want ErrNotFound.", err)
+	}
+}
+
+func TestCompanyGetError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.Company.Get(context.Background(), "123")
+		return err
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 const (
@@ -25,6 +26,14 @@ type CostCenter struct {
 	Company *Company `json:"company,omitempty"`
 }
 
+// reqCostCenter is the body of cost center updates, which replace
+// the whole cost center. Enabled is always sent, as false would be
+// omitted from a CostCenter.
+type reqCostCenter struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
 type CostCenterService service
 
 func (c *CostCenterService) Find(ctx context.Context, f Filter) ([]*CostCenter, error) {
@@ -32,7 +41,7 @@ func (c *CostCenterService) Find(ctx context.Context, f Filter) ([]*CostCenter,
 
 	v := f.values(ccFields)
 
-	err := c.client.Request(ctx, http.MethodGet, string(costCentersEndpoint.Query(v)), nil, &costCenters)
+	err := c.client.Request(withOperation(ctx, "CostCenter.Find"), http.MethodGet, string(costCentersEndpoint.Query(v)), nil, &costCenters)
 	if err != nil {
 		return nil, err
 	}
@@ -40,10 +49,89 @@ func (c *CostCenterService) Find(ctx context.Context, f Filter) ([]*CostCenter,
 	return costCenters, nil
 }
 
+// Get returns the cost center with the ID. The error
+// matches ErrNotFound if it doesn't exist.
+func (c *CostCenterService) Get(ctx context.Context, id int64) (*CostCenter, error) {
+	cc := new(CostCenter)
+
+	endpoint := fmt.Sprintf(string(costCenterEndpoint), id)
+
+	err := c.client.Request(withOperation(ctx, "CostCenter.Get"), http.MethodGet, endpoint, nil, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	return cc, nil
+}
+
+// Update replaces the name and enabled status of the cost center
+// with the ID, keeping its employee assignments. A false Enabled
+// disables it: use Patch to change only some of the fields.
+func (c *CostCenterService) Update(ctx context.Context, cc CostCenter) (*CostCenter, error) {
+	res := new(CostCenter)
+
+	endpoint := fmt.Sprintf(string(costCenterEndpoint), cc.ID)
+
+	err := c.client.Request(withOperation(ctx, "CostCenter.Update"), http.MethodPut, endpoint, reqCostCenter{cc.Name, cc.Enabled}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Patch updates only the fields set in the patch,
+// unlike Update which sends the whole cost center.
+func (c *CostCenterService) Patch(ctx context.Context, id int64, p CostCenterPatch) (*CostCenter, error) {
+	return c.patch(withOperation(ctx, "CostCenter.Patch"), id, p)
+}
+
+func (c *CostCenterService) patch(ctx context.Context, id int64, p CostCenterPatch) (*CostCenter, error) {
+	res := new(CostCenter)
+
+	endpoint := fmt.Sprintf(string(costCenterEndpoint), id)
+
+	err := c.client.Request(ctx, http.MethodPatch, endpoint, p, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Enable enables the cost center with the ID.
+func (c *CostCenterService) Enable(ctx context.Context, id int64) (*CostCenter, error) {
+	var p CostCenterPatch
+	p.Enabled.Set(true)
+
+	return c.patch(withOperation(ctx, "CostCenter.Enable"), id, p)
+}
+
+// Disable disables the cost center with the ID. Its employees
+// can't request rides charged to it until it's enabled again.
+func (c *CostCenterService) Disable(ctx context.Context, id int64) (*CostCenter, error) {
+	var p CostCenterPatch
+	p.Enabled.Set(false)
+
+	return c.patch(withOperation(ctx, "CostCenter.Disable"), id, p)
+}
+
+// Create creates the cost center. If the request is retried after an
+// ambiguous failure, the cost center is looked up by name first so it's
+// not created twice.
 func (c *CostCenterService) Create(ctx context.Context, newCC CostCenter) (*CostCenter, error) {
 	cc := new(CostCenter)
 
-	err := c.client.Request(context.Background(), http.MethodPost, string(costCentersEndpoint), newCC, cc)
+	ctx = withDedupe(ctx, func(ctx context.Context) (bool, error) {
+		found, err := c.findByName(ctx, newCC.Name)
+		if err != nil || found == nil {
+			return false, err
+		}
+		*cc = *found
+		return true, nil
+	})
+
+	err := c.client.Request(withOperation(ctx, "CostCenter.Create"), http.MethodPost, string(costCentersEndpoint), newCC, cc)
 	if err != nil {
 		return nil, err
 	}
@@ -51,9 +139,86 @@ func (c *CostCenterService) Create(ctx context.Context, newCC CostCenter) (*Cost
 	return cc, nil
 }
 
+// findByName returns the cost center with the exact name, if any.
+func (c *CostCenterService) findByName(ctx context.Context, name string) (*CostCenter, error) {
+	ccs, err := c.Find(ctx, Filter{"search": name})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cc := range ccs {
+		if strings.EqualFold(cc.Name, name) {
+			return cc, nil
+		}
+	}
+
+	return nil, nil
+}
+
 func (c *CostCenterService) Remove(ctx context.Context, id int64) error {
 
 	endpoint := fmt.Sprintf(string(costCenterEndpoint), id)
 
-	return c.client.Request(context.Background(), http.MethodDelete, endpoint, nil, nil)
+	return c.client.Request(withOperation(ctx, "CostCenter.Remove"), http.MethodDelete, endpoint, nil, nil)
+}
+
+// CostCenterIterator iterates over all the cost centers returned by Find,
+// walking through every page.
+type CostCenterIterator struct {
+	p   *pager
+	buf []*CostCenter
+	cur *CostCenter
+}
+
+// Next advances to the next cost center. It returns false when there are
+// no more cost centers, the context is done or an error happened.
+func (it *CostCenterIterator) Next() bool {
+	if it.p.checkContext() {
+		it.cur = nil
+		return false
+	}
+
+	for len(it.buf) == 0 {
+		if !it.p.next() {
+			it.cur = nil
+			return false
+		}
+	}
+
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// CostCenter returns the current cost center.
+func (it *CostCenterIterator) CostCenter() *CostCenter {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *CostCenterIterator) Err() error {
+	return it.p.err
+}
+
+// All returns an iterator over all the cost centers matching the filter.
+// The iteration starts at the filter page or at the first one.
+func (c *CostCenterService) All(ctx context.Context, f Filter) *CostCenterIterator {
+	it := new(CostCenterIterator)
+	it.p = newPager(ctx, f, func(ctx context.Context, f Filter) (int, error) {
+		var err error
+		it.buf, err = c.Find(ctx, f)
+		return len(it.buf), err
+	})
+	return it
+}
+
+// Each calls fn for all the cost centers matching the filter.
+// It stops at the first error returned by fn.
+func (c *CostCenterService) Each(ctx context.Context, f Filter, fn func(*CostCenter) error) error {
+	it := c.All(ctx, f)
+	for it.Next() {
+		if err := fn(it.CostCenter()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
 }
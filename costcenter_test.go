@@ -1,9 +1,13 @@
 package taxis99
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"reflect"
 	"testing"
 )
 
@@ -104,3 +108,203 @@ func TestCostCenterRemoveError(t *testing.T) {
 		return c.CostCenter.Remove(context.Background(), 0)
 	})
 }
+
+func TestCostCenterGet(t *testing.T) {
+	testCases := []struct {
+		id   int64
+		want string
+	}{
+		{25, fmt.Sprintf(string(costCenterEndpoint), 25)},
+		{28, fmt.Sprintf(string(costCenterEndpoint), 28)},
+	}
+
+	for _, tc := range testCases {
+		testPath(t, tc.want, func(c *Client) error {
+			_, err := c.CostCenter.Get(context.Background(), tc.id)
+			return err
+		})
+	}
+
+	testMethod(t, http.MethodGet, func(c *Client) error {
+		_, err := c.CostCenter.Get(context.Background(), 20)
+		return err
+	})
+
+	testResponseBody(t, [][]byte{
+		[]byte(`{"id":25,"name":"IT","enabled":true}`),
+	}, func(c *Client) (interface{}, error) {
+		return c.CostCenter.Get(context.Background(), 25)
+	})
+}
+
+func TestCostCenterGetError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.CostCenter.Get(context.Background(), 0)
+		return err
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		defer srv.Close()
+
+		_, err := client.CostCenter.Get(context.Background(), 25)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Got error '%v'; want ErrNotFound.", err)
+		}
+	})
+}
+
+func TestCostCenterUpdate(t *testing.T) {
+	testPath(t, fmt.Sprintf(string(costCenterEndpoint), 25), func(c *Client) error {
+		_, err := c.CostCenter.Update(context.Background(), CostCenter{ID: 25})
+		return err
+	})
+
+	testMethod(t, http.MethodPut, func(c *Client) error {
+		_, err := c.CostCenter.Update(context.Background(), CostCenter{ID: 25})
+		return err
+	})
+
+	testResponseBody(t, [][]byte{
+		[]byte(`{"id":25,"name":"Engineering","enabled":true}`),
+	}, func(c *Client) (interface{}, error) {
+		return c.CostCenter.Update(context.Background(), CostCenter{ID: 25})
+	})
+
+	testRequestBody(t, []func(*Client) ([]byte, error){
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"name":"Engineering","enabled":true}`)
+			_, err = c.CostCenter.Update(context.Background(), CostCenter{ID: 25, Name: "Engineering", Enabled: true})
+			return
+		},
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"name":"Engineering","enabled":false}`)
+			_, err = c.CostCenter.Update(context.Background(), CostCenter{ID: 25, Name: "Engineering"})
+			return
+		},
+	})
+}
+
+func TestCostCenterUpdateError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.CostCenter.Update(context.Background(), CostCenter{ID: 25})
+		return err
+	})
+}
+
+func TestCostCenterPatch(t *testing.T) {
+	var p CostCenterPatch
+	p.Name.Set("Engineering")
+
+	testPath(t, fmt.Sprintf(string(costCenterEndpoint), 25), func(c *Client) error {
+		_, err := c.CostCenter.Patch(context.Background(), 25, p)
+		return err
+	})
+
+	testMethod(t, http.MethodPatch, func(c *Client) error {
+		_, err := c.CostCenter.Patch(context.Background(), 25, p)
+		return err
+	})
+
+	testResponseBody(t, [][]byte{
+		[]byte(`{"id":25,"name":"Engineering","enabled":true}`),
+	}, func(c *Client) (interface{}, error) {
+		return c.CostCenter.Patch(context.Background(), 25, p)
+	})
+
+	testRequestBody(t, []func(*Client) ([]byte, error){
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"name":"Engineering"}`)
+			_, err = c.CostCenter.Patch(context.Background(), 25, p)
+			return
+		},
+		func(c *Client) (want []byte, err error) {
+			var p CostCenterPatch
+			p.Enabled.Set(false)
+
+			want = []byte(`{"enabled":false}`)
+			_, err = c.CostCenter.Patch(context.Background(), 25, p)
+			return
+		},
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{}`)
+			_, err = c.CostCenter.Patch(context.Background(), 25, CostCenterPatch{})
+			return
+		},
+	})
+}
+
+func TestCostCenterPatchError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.CostCenter.Patch(context.Background(), 25, CostCenterPatch{})
+		return err
+	})
+}
+
+func TestCostCenterEnable(t *testing.T) {
+	testCases := []struct {
+		name   string
+		update func(c *Client) (*CostCenter, error)
+		want   []byte
+	}{
+		{"Enable", func(c *Client) (*CostCenter, error) {
+			return c.CostCenter.Enable(context.Background(), 25)
+		}, []byte(`{"enabled":true}`)},
+		{"Disable", func(c *Client) (*CostCenter, error) {
+			return c.CostCenter.Disable(context.Background(), 25)
+		}, []byte(`{"enabled":false}`)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			var body []byte
+			client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+				got = append(got, r.Method+" "+r.URL.Path)
+				if r.Method == http.MethodPatch {
+					body, _ = ioutil.ReadAll(r.Body)
+				}
+				w.Write([]byte(`{"id":25,"name":"IT","enabled":true}`))
+			})
+			defer srv.Close()
+
+			if _, err := tc.update(client); err != nil {
+				t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+			}
+
+			want := []string{"PATCH /costcenters/25"}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Got requests %v; want %v.", got, want)
+			}
+
+			if !bytes.Equal(bytes.TrimSpace(body), tc.want) {
+				t.Errorf("Got request body %s; want %s.", body, tc.want)
+			}
+		})
+	}
+}
+
+func TestCostCenterEnableError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.CostCenter.Enable(context.Background(), 25)
+		return err
+	})
+
+	testError(t, func(c *Client) error {
+		_, err := c.CostCenter.Disable(context.Background(), 25)
+		return err
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		defer srv.Close()
+
+		if _, err := client.CostCenter.Disable(context.Background(), 25); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Got error '%v'; want ErrNotFound.", err)
+		}
+	})
+}
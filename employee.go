@@ -2,6 +2,7 @@ package taxis99
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -44,6 +45,10 @@ type reqEmployee struct {
 	SendWelcomeEmail bool      `json:"sendWelcomeEmail"`
 }
 
+type reqEmployeePatch struct {
+	Employee EmployeePatch `json:"employee"`
+}
+
 type EmployeeService service
 
 func (e *EmployeeService) Find(ctx context.Context, f Filter) ([]*Employee, error) {
@@ -51,7 +56,7 @@ func (e *EmployeeService) Find(ctx context.Context, f Filter) ([]*Employee, erro
 
 	v := f.values(ccFields)
 
-	err := e.client.Request(ctx, http.MethodGet, string(employeesEndpoint.Query(v)), nil, &employees)
+	err := e.client.Request(withOperation(ctx, "Employee.Find"), http.MethodGet, string(employeesEndpoint.Query(v)), nil, &employees)
 	if err != nil {
 		return nil, err
 	}
@@ -59,12 +64,27 @@ func (e *EmployeeService) Find(ctx context.Context, f Filter) ([]*Employee, erro
 	return employees, nil
 }
 
+// Get returns the employee with the ID. The error
+// matches ErrNotFound if it doesn't exist.
+func (e *EmployeeService) Get(ctx context.Context, id int64) (*Employee, error) {
+	emp := new(Employee)
+
+	endpoint := fmt.Sprintf(string(employeeEndpoint), id)
+
+	err := e.client.Request(withOperation(ctx, "Employee.Get"), http.MethodGet, endpoint, nil, emp)
+	if err != nil {
+		return nil, err
+	}
+
+	return emp, nil
+}
+
 func (e *EmployeeService) FindByExternalID(ctx context.Context, extID int64) ([]*Employee, error) {
 	var employees []*Employee
 
 	endpoint := fmt.Sprintf(string(employeesExternalIdEndpoint), extID)
 
-	err := e.client.Request(ctx, http.MethodGet, endpoint, nil, &employees)
+	err := e.client.Request(withOperation(ctx, "Employee.FindByExternalID"), http.MethodGet, endpoint, nil, &employees)
 	if err != nil {
 		return nil, err
 	}
@@ -72,15 +92,32 @@ func (e *EmployeeService) FindByExternalID(ctx context.Context, extID int64) ([]
 	return employees, nil
 }
 
+// Create creates the employee. If the request is retried after an
+// ambiguous failure and the employee has an ExternalID, it's looked
+// up first so it's not created twice.
 func (e *EmployeeService) Create(ctx context.Context, emp Employee, sendEmail bool) (*Employee, error) {
 	res := new(Employee)
 
+	if emp.ExternalID != 0 {
+		ctx = withDedupe(ctx, func(ctx context.Context) (bool, error) {
+			found, err := e.FindByExternalID(ctx, emp.ExternalID)
+			if errors.Is(err, ErrNotFound) {
+				return false, nil
+			}
+			if err != nil || len(found) == 0 {
+				return false, err
+			}
+			*res = *found[0]
+			return true, nil
+		})
+	}
+
 	newEmp := reqEmployee{
 		Employee:         &emp,
 		SendWelcomeEmail: sendEmail,
 	}
 
-	err := e.client.Request(context.Background(), http.MethodPost, string(employeesEndpoint), newEmp, res)
+	err := e.client.Request(withOperation(ctx, "Employee.Create"), http.MethodPost, string(employeesEndpoint), newEmp, res)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +134,26 @@ func (e *EmployeeService) Update(ctx context.Context, emp Employee) (*Employee,
 
 	endpoint := fmt.Sprintf(string(employeeEndpoint), emp.ID)
 
-	err := e.client.Request(context.Background(), http.MethodPut, endpoint, updatedEmp, res)
+	err := e.client.Request(withOperation(ctx, "Employee.Update"), http.MethodPut, endpoint, updatedEmp, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Patch updates only the fields set or cleared in the patch,
+// unlike Update which sends the whole employee.
+func (e *EmployeeService) Patch(ctx context.Context, id int64, p EmployeePatch) (*Employee, error) {
+	return e.patch(withOperation(ctx, "Employee.Patch"), id, p)
+}
+
+func (e *EmployeeService) patch(ctx context.Context, id int64, p EmployeePatch) (*Employee, error) {
+	res := new(Employee)
+
+	endpoint := fmt.Sprintf(string(employeeEndpoint), id)
+
+	err := e.client.Request(ctx, http.MethodPatch, endpoint, reqEmployeePatch{p}, res)
 	if err != nil {
 		return nil, err
 	}
@@ -105,10 +161,38 @@ func (e *EmployeeService) Update(ctx context.Context, emp Employee) (*Employee,
 	return res, nil
 }
 
+// PatchFields updates only the employee fields in the mask, named
+// as in JSON, like phone. See NewEmployeePatch for zero values.
+func (e *EmployeeService) PatchFields(ctx context.Context, emp Employee, mask ...string) (*Employee, error) {
+	p, err := NewEmployeePatch(emp, mask...)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.patch(withOperation(ctx, "Employee.PatchFields"), emp.ID, p)
+}
+
+// Enable allows the employee to request rides again.
+func (e *EmployeeService) Enable(ctx context.Context, id int64) (*Employee, error) {
+	var p EmployeePatch
+	p.Enabled.Set(true)
+
+	return e.patch(withOperation(ctx, "Employee.Enable"), id, p)
+}
+
+// Disable blocks the employee from requesting rides, like when
+// offboarding. Update can't do it, as false Enabled is omitted.
+func (e *EmployeeService) Disable(ctx context.Context, id int64) (*Employee, error) {
+	var p EmployeePatch
+	p.Enabled.Set(false)
+
+	return e.patch(withOperation(ctx, "Employee.Disable"), id, p)
+}
+
 func (e *EmployeeService) Remove(ctx context.Context, id int64) error {
 	endpoint := fmt.Sprintf(string(employeeEndpoint), id)
 
-	return e.client.Request(context.Background(), http.MethodDelete, endpoint, nil, nil)
+	return e.client.Request(withOperation(ctx, "Employee.Remove"), http.MethodDelete, endpoint, nil, nil)
 }
 
 func (e *EmployeeService) FindCostCenters(ctx context.Context, empID int64) ([]*CostCenter, error) {
@@ -116,7 +200,7 @@ func (e *EmployeeService) FindCostCenters(ctx context.Context, empID int64) ([]*
 
 	endpoint := fmt.Sprintf(string(employeeCostCentersEndpoint), empID)
 
-	err := e.client.Request(context.Background(), http.MethodGet, endpoint, nil, &costCenters)
+	err := e.client.Request(withOperation(ctx, "Employee.FindCostCenters"), http.MethodGet, endpoint, nil, &costCenters)
 	if err != nil {
 		return nil, err
 	}
@@ -134,9 +218,70 @@ func (e *EmployeeService) UpdateCostCenters(ctx context.Context, empID int64, co
 
 	newCostCenters := updateCostCenters{costCenterIDs}
 
-	err := e.client.Request(context.Background(), http.MethodPatch, endpoint, newCostCenters, &ids)
+	err := e.client.Request(withOperation(ctx, "Employee.UpdateCostCenters"), http.MethodPatch, endpoint, newCostCenters, &ids)
 	if err != nil {
 		return nil, err
 	}
 	return ids, nil
 }
+
+// EmployeeIterator iterates over all the employees returned by Find,
+// walking through every page.
+type EmployeeIterator struct {
+	p   *pager
+	buf []*Employee
+	cur *Employee
+}
+
+// Next advances to the next employee. It returns false when there are
+// no more employees, the context is done or an error happened.
+func (it *EmployeeIterator) Next() bool {
+	if it.p.checkContext() {
+		it.cur = nil
+		return false
+	}
+
+	for len(it.buf) == 0 {
+		if !it.p.next() {
+			it.cur = nil
+			return false
+		}
+	}
+
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Employee returns the current employee.
+func (it *EmployeeIterator) Employee() *Employee {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *EmployeeIterator) Err() error {
+	return it.p.err
+}
+
+// All returns an iterator over all the employees matching the filter.
+// The iteration starts at the filter page or at the first one.
+func (e *EmployeeService) All(ctx context.Context, f Filter) *EmployeeIterator {
+	it := new(EmployeeIterator)
+	it.p = newPager(ctx, f, func(ctx context.Context, f Filter) (int, error) {
+		var err error
+		it.buf, err = e.Find(ctx, f)
+		return len(it.buf), err
+	})
+	return it
+}
+
+// Each calls fn for all the employees matching the filter.
+// It stops at the first error returned by fn.
+func (e *EmployeeService) Each(ctx context.Context, f Filter, fn func(*Employee) error) error {
+	it := e.All(ctx, f)
+	for it.Next() {
+		if err := fn(it.Employee()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
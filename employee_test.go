@@ -2,6 +2,7 @@ package taxis99
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -304,3 +305,208 @@ func TestEmployeeUpdateCostCentersError(t *testing.T) {
 		return err
 	})
 }
+
+func TestEmployeeGet(t *testing.T) {
+	testCases := []struct {
+		id   int64
+		want string
+	}{
+		{25, fmt.Sprintf(string(employeeEndpoint), 25)},
+		{28, fmt.Sprintf(string(employeeEndpoint), 28)},
+	}
+
+	for _, tc := range testCases {
+		testPath(t, tc.want, func(c *Client) error {
+			_, err := c.Employee.Get(context.Background(), tc.id)
+			return err
+		})
+	}
+
+	testMethod(t, http.MethodGet, func(c *Client) error {
+		_, err := c.Employee.Get(context.Background(), 20)
+		return err
+	})
+
+	testResponseBody(t, [][]byte{
+		[]byte(`{"id":25,"name":"José Santos","email":"jose@example.com","externalId":42}`),
+	}, func(c *Client) (interface{}, error) {
+		return c.Employee.Get(context.Background(), 25)
+	})
+}
+
+func TestEmployeeGetError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.Employee.Get(context.Background(), 0)
+		return err
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		defer srv.Close()
+
+		_, err := client.Employee.Get(context.Background(), 25)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Got error '%v'; want ErrNotFound.", err)
+		}
+	})
+}
+
+func TestEmployeePatch(t *testing.T) {
+	testPath(t, fmt.Sprintf(string(employeeEndpoint), 25), func(c *Client) error {
+		_, err := c.Employee.Patch(context.Background(), 25, EmployeePatch{})
+		return err
+	})
+
+	testMethod(t, http.MethodPatch, func(c *Client) error {
+		_, err := c.Employee.Patch(context.Background(), 25, EmployeePatch{})
+		return err
+	})
+
+	testResponseBody(t, [][]byte{
+		[]byte(`{"id":25,"name":"José Santos","enabled":true}`),
+	}, func(c *Client) (interface{}, error) {
+		return c.Employee.Patch(context.Background(), 25, EmployeePatch{})
+	})
+
+	testRequestBody(t, []func(*Client) ([]byte, error){
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"employee":{"supervisorId":null,"enabled":false}}`)
+			var p EmployeePatch
+			p.SupervisorID.Clear()
+			p.Enabled.Set(false)
+			_, err = c.Employee.Patch(context.Background(), 25, p)
+			return
+		},
+	})
+}
+
+func TestEmployeePatchError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.Employee.Patch(context.Background(), 25, EmployeePatch{})
+		return err
+	})
+}
+
+func TestEmployeeEnable(t *testing.T) {
+	for _, id := range []int64{25, 28} {
+		testPath(t, fmt.Sprintf(string(employeeEndpoint), id), func(c *Client) error {
+			_, err := c.Employee.Enable(context.Background(), id)
+			return err
+		})
+
+		testPath(t, fmt.Sprintf(string(employeeEndpoint), id), func(c *Client) error {
+			_, err := c.Employee.Disable(context.Background(), id)
+			return err
+		})
+	}
+
+	testMethod(t, http.MethodPatch, func(c *Client) error {
+		_, err := c.Employee.Enable(context.Background(), 25)
+		return err
+	})
+
+	testMethod(t, http.MethodPatch, func(c *Client) error {
+		_, err := c.Employee.Disable(context.Background(), 25)
+		return err
+	})
+
+	testRequestBody(t, []func(*Client) ([]byte, error){
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"employee":{"enabled":true}}`)
+			_, err = c.Employee.Enable(context.Background(), 25)
+			return
+		},
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"employee":{"enabled":false}}`)
+			_, err = c.Employee.Disable(context.Background(), 25)
+			return
+		},
+	})
+}
+
+func TestEmployeeEnableError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.Employee.Enable(context.Background(), 25)
+		return err
+	})
+
+	testError(t, func(c *Client) error {
+		_, err := c.Employee.Disable(context.Background(), 25)
+		return err
+	})
+}
+
+func TestEmployeePatchFields(t *testing.T) {
+	emp := Employee{
+		ID:         25,
+		Name:       "José Santos",
+		Email:      "jose@example.com",
+		Phone:      &Phone{Number: "11999999999", Country: "BR"},
+		ExternalID: 42,
+		Categories: []string{"pop99", "comfort"},
+	}
+
+	testPath(t, fmt.Sprintf(string(employeeEndpoint), 25), func(c *Client) error {
+		_, err := c.Employee.PatchFields(context.Background(), emp, "phone")
+		return err
+	})
+
+	testMethod(t, http.MethodPatch, func(c *Client) error {
+		_, err := c.Employee.PatchFields(context.Background(), emp, "phone")
+		return err
+	})
+
+	testResponseBody(t, [][]byte{
+		[]byte(`{"id":25,"name":"José Santos","phone":{"number":"11999999999","country":"BR"}}`),
+	}, func(c *Client) (interface{}, error) {
+		return c.Employee.PatchFields(context.Background(), emp, "phone")
+	})
+
+	testRequestBody(t, []func(*Client) ([]byte, error){
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"employee":{"phone":{"number":"11999999999","country":"BR"}}}`)
+			_, err = c.Employee.PatchFields(context.Background(), emp, "phone")
+			return
+		},
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"employee":{"email":"jose@example.com","externalId":42,"categories":["pop99","comfort"]}}`)
+			_, err = c.Employee.PatchFields(context.Background(), emp, "categories", "email", "externalId")
+			return
+		},
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"employee":{"nationalId":null,"supervisorId":null,"enabled":false}}`)
+			_, err = c.Employee.PatchFields(context.Background(), emp, "supervisorId", "enabled", "nationalId")
+			return
+		},
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"employee":{}}`)
+			_, err = c.Employee.PatchFields(context.Background(), emp)
+			return
+		},
+	})
+}
+
+func TestEmployeePatchFieldsError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.Employee.PatchFields(context.Background(), Employee{ID: 25}, "name")
+		return err
+	})
+
+	t.Run("UnknownField", func(t *testing.T) {
+		var called bool
+		request := func(ctx context.Context, method, path string, body, output interface{}) error {
+			called = true
+			return nil
+		}
+		c := newMockRequesterClient(mockRequester(request))
+
+		if _, err := c.Employee.PatchFields(context.Background(), Employee{ID: 25}, "company"); err == nil {
+			t.Error("Got error nil for an unknown field; want it not nil.")
+		}
+		if called {
+			t.Error("Got the request sent; want it not sent.")
+		}
+	})
+}
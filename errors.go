@@ -0,0 +1,193 @@
+package taxis99
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const headerRequestID = "X-Request-Id"
+
+var (
+	// ErrUnauthorized is returned when the API key is missing, invalid
+	// or not allowed to access the resource (401 and 403).
+	ErrUnauthorized = errors.New("taxis99: unauthorized")
+	// ErrNotFound is returned when the resource does not exist (404).
+	ErrNotFound = errors.New("taxis99: not found")
+	// ErrConflict is returned when the resource conflicts with an existing one (409).
+	ErrConflict = errors.New("taxis99: conflict")
+	// ErrRateLimited is returned when the API quota is exceeded (429).
+	ErrRateLimited = errors.New("taxis99: rate limited")
+	// ErrServer is returned when the API fails to handle the request (5xx).
+	ErrServer = errors.New("taxis99: server error")
+)
+
+// ApiError implements the error interface
+// and returns infos from the request.
+type APIError struct {
+	StatusCode int
+	// Code is the error code returned by the API, if any.
+	Code string
+	Msg  string
+	Err  error
+
+	// Body is the raw response body.
+	Body []byte
+	// RequestID is the request identifier sent by the API, if any.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Error Status Code: %d; Message: %s.", e.StatusCode, e.Msg)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the status code or the API error code maps
+// to the target error, so callers can use errors.Is(err, ErrNotFound).
+func (e *APIError) Is(target error) bool {
+	if err := statusError(e.StatusCode); err != nil && err == target {
+		return true
+	}
+	if err, ok := codeErrors[e.Code]; ok && err == target {
+		return true
+	}
+	return false
+}
+
+// statusError returns the package error for the status code, if any.
+func statusError(status int) error {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrUnauthorized
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusConflict:
+		return ErrConflict
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status >= http.StatusInternalServerError:
+		return ErrServer
+	}
+	return nil
+}
+
+// codeErrors maps the API error codes to the package errors.
+var codeErrors = map[string]error{
+	codeNoDriversAvailable:  ErrNoDriversAvailable,
+	codeRideAlreadyFinished: ErrRideAlreadyFinished,
+}
+
+// FieldError is the validation error of a single field.
+type FieldError struct {
+	Code    string `json:"code,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (e FieldError) String() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError lists every field rejected by the API in an
+// unprocessable entity (422) response. It's reachable from the
+// *APIError with errors.As.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.String()
+	}
+	return fmt.Sprintf("taxis99: validation failed: %s", strings.Join(msgs, "; "))
+}
+
+// Is reports whether any field error code maps to the target error.
+func (e *ValidationError) Is(target error) bool {
+	for _, fe := range e.Errors {
+		if err, ok := codeErrors[fe.Code]; ok && err == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Field returns the first error of the field, if any.
+func (e *ValidationError) Field(field string) (FieldError, bool) {
+	for _, fe := range e.Errors {
+		if fe.Field == field {
+			return fe, true
+		}
+	}
+	return FieldError{}, false
+}
+
+// parseFieldErrors decodes the field errors from the body, which
+// can be a single error, a list of errors or an object with
+// the list in the errors field.
+func parseFieldErrors(body []byte) []FieldError {
+	var list []FieldError
+	if err := json.Unmarshal(body, &list); err == nil {
+		return list
+	}
+
+	var wrapper struct {
+		FieldError
+		Errors []FieldError `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		return nil
+	}
+
+	if len(wrapper.Errors) > 0 {
+		return wrapper.Errors
+	}
+
+	if wrapper.FieldError != (FieldError{}) {
+		return []FieldError{wrapper.FieldError}
+	}
+
+	return nil
+}
+
+// newAPIError returns the *APIError for a response with
+// an error status code, keeping its body for debugging.
+func newAPIError(res *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: res.StatusCode,
+		Body:       body,
+		RequestID:  res.Header.Get(headerRequestID),
+	}
+
+	// The body is not always a JSON, in which case the status text is used.
+	fieldErrs := parseFieldErrors(body)
+
+	var msgs []string
+	for _, fe := range fieldErrs {
+		if fe.Message != "" {
+			msgs = append(msgs, fe.Message)
+		}
+	}
+	if len(fieldErrs) > 0 {
+		e.Code = fieldErrs[0].Code
+	}
+	if len(msgs) == 0 {
+		msgs = append(msgs, http.StatusText(res.StatusCode))
+	}
+	e.Msg = fmt.Sprintf("taxis99: %s", strings.Join(msgs, "; "))
+
+	if res.StatusCode == http.StatusUnprocessableEntity && len(fieldErrs) > 0 {
+		e.Err = &ValidationError{Errors: fieldErrs}
+	}
+
+	return e
+}
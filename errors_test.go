@@ -0,0 +1,208 @@
+package taxis99
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClientRequestStatusError(t *testing.T) {
+	testCases := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusInternalServerError, ErrServer},
+		{http.StatusBadGateway, ErrServer},
+		{http.StatusBadRequest, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			body := []byte(`{"name":"test","message":"Something went wrong"}`)
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(headerRequestID, "req-123")
+				w.WriteHeader(tc.status)
+				w.Write(body)
+			}
+
+			client, srv := newMockServer(nil, handler)
+			defer srv.Close()
+
+			var out struct {
+				Name string `json:"name"`
+			}
+			err := client.Request(context.Background(), http.MethodGet, "", nil, &out)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Got error '%v'; want it to be of APIError type.", err)
+			}
+
+			if out.Name != "" {
+				t.Errorf("Got output decoded %+v; want it to be empty.", out)
+			}
+
+			if apiErr.StatusCode != tc.status {
+				t.Errorf("Got StatusCode %d; want %d.", apiErr.StatusCode, tc.status)
+			}
+
+			if !bytes.Equal(apiErr.Body, body) {
+				t.Errorf("Got Body %s; want %s.", apiErr.Body, body)
+			}
+
+			if apiErr.RequestID != "req-123" {
+				t.Errorf("Got RequestID '%s'; want 'req-123'.", apiErr.RequestID)
+			}
+
+			if tc.want != nil && !errors.Is(err, tc.want) {
+				t.Errorf("Got error '%v'; want it to be '%v'.", err, tc.want)
+			}
+
+			for _, sentinel := range []error{ErrUnauthorized, ErrNotFound, ErrConflict, ErrRateLimited, ErrServer} {
+				if sentinel != tc.want && errors.Is(err, sentinel) {
+					t.Errorf("Got error '%v' matching '%v'; want it not to.", err, sentinel)
+				}
+			}
+		})
+	}
+}
+
+func TestClientRequestStatusErrorMessage(t *testing.T) {
+	testCases := []struct {
+		body    string
+		wantMsg string
+	}{
+		{`{"message":"Employee not found"}`, "taxis99: Employee not found"},
+		{`not found`, "taxis99: Not Found"},
+		{``, "taxis99: Not Found"},
+	}
+
+	for _, tc := range testCases {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(tc.body))
+		}
+
+		client, srv := newMockServer(nil, handler)
+		defer srv.Close()
+
+		err := client.Request(context.Background(), http.MethodGet, "", nil, nil)
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Got error '%v'; want it to be of APIError type.", err)
+		}
+
+		if apiErr.Msg != tc.wantMsg {
+			t.Errorf("Got Msg '%s'; want '%s'.", apiErr.Msg, tc.wantMsg)
+		}
+	}
+}
+
+func TestClientRequestValidationError(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		want []FieldError
+	}{
+		{
+			"Single",
+			`{"code":"error.invalidEmail","field":"employee.email","message":"Invalid email"}`,
+			[]FieldError{
+				{"error.invalidEmail", "employee.email", "Invalid email"},
+			},
+		},
+		{
+			"Array",
+			`[{"code":"error.invalidEmail","field":"employee.email","message":"Invalid email"},{"code":"error.duplicatedNationalId","field":"employee.nationalId","message":"Duplicated national ID"}]`,
+			[]FieldError{
+				{"error.invalidEmail", "employee.email", "Invalid email"},
+				{"error.duplicatedNationalId", "employee.nationalId", "Duplicated national ID"},
+			},
+		},
+		{
+			"Errors",
+			`{"errors":[{"field":"employee.phone","message":"error.invalidPhoneNumber"},{"field":"employee.email","message":"error.invalidEmail"}]}`,
+			[]FieldError{
+				{"", "employee.phone", "error.invalidPhoneNumber"},
+				{"", "employee.email", "error.invalidEmail"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(tc.body))
+			}
+
+			client, srv := newMockServer(nil, handler)
+			defer srv.Close()
+
+			err := client.Request(context.Background(), http.MethodPost, "employees", nil, nil)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Got error '%v'; want it to be of APIError type.", err)
+			}
+
+			var valErr *ValidationError
+			if !errors.As(err, &valErr) {
+				t.Fatalf("Got error '%v'; want it to be of ValidationError type.", err)
+			}
+
+			if !reflect.DeepEqual(valErr.Errors, tc.want) {
+				t.Errorf("Got field errors %+v; want %+v.", valErr.Errors, tc.want)
+			}
+
+			for _, fe := range tc.want {
+				if got, ok := valErr.Field(fe.Field); !ok || got != fe {
+					t.Errorf("Got Field(%s) %+v; want %+v.", fe.Field, got, fe)
+				}
+			}
+		})
+	}
+}
+
+func TestClientRequestValidationErrorInvalidBody(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`invalid`))
+	}
+
+	client, srv := newMockServer(nil, handler)
+	defer srv.Close()
+
+	err := client.Request(context.Background(), http.MethodPost, "employees", nil, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Got error '%v'; want it to be of APIError type.", err)
+	}
+
+	var valErr *ValidationError
+	if errors.As(err, &valErr) {
+		t.Errorf("Got ValidationError %+v; want it to be nil.", valErr)
+	}
+}
+
+func TestValidationErrorError(t *testing.T) {
+	err := &ValidationError{Errors: []FieldError{
+		{"error.invalidEmail", "employee.email", "Invalid email"},
+		{"", "", "Invalid employee"},
+	}}
+
+	want := "taxis99: validation failed: employee.email: Invalid email; Invalid employee"
+	if got := err.Error(); got != want {
+		t.Errorf("Got error message '%s'; want '%s'.", got, want)
+	}
+}
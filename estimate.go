@@ -0,0 +1,47 @@
+package taxis99
+
+import (
+	"context"
+	"net/http"
+)
+
+const estimatesEndpoint endpoint = `estimates`
+
+// EstimateRequest is the trip to be estimated. CostCenterID
+// and EmployeeID are optional and narrow the estimate to the
+// categories allowed for them.
+type EstimateRequest struct {
+	Origin       *Location `json:"origin,omitempty"`
+	Destination  *Location `json:"destination,omitempty"`
+	CostCenterID int64     `json:"costCenterId,omitempty"`
+	EmployeeID   int64     `json:"employeeId,omitempty"`
+}
+
+// PriceRange is the lower and upper bound of an estimated price.
+type PriceRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Estimate is the estimated price of a ride category.
+type Estimate struct {
+	Category string      `json:"category,omitempty"`
+	Price    *PriceRange `json:"price,omitempty"`
+	// ETA is the pickup estimated time of arrival in seconds.
+	ETA      int64  `json:"eta,omitempty"`
+	Currency string `json:"currency,omitempty"`
+}
+
+type EstimateService service
+
+// Find returns the estimates for each category available for the trip.
+func (e *EstimateService) Find(ctx context.Context, req EstimateRequest) ([]*Estimate, error) {
+	var estimates []*Estimate
+
+	err := e.client.Request(withOperation(ctx, "Estimate.Find"), http.MethodPost, string(estimatesEndpoint), req, &estimates)
+	if err != nil {
+		return nil, err
+	}
+
+	return estimates, nil
+}
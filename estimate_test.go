@@ -0,0 +1,54 @@
+package taxis99
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestEstimateFind(t *testing.T) {
+	testPath(t, string(estimatesEndpoint), func(c *Client) error {
+		_, err := c.Estimate.Find(context.Background(), EstimateRequest{})
+		return err
+	})
+
+	testMethod(t, http.MethodPost, func(c *Client) error {
+		_, err := c.Estimate.Find(context.Background(), EstimateRequest{})
+		return err
+	})
+
+	testResponseBody(t, [][]byte{
+		[]byte(`[{"category":"pop99","price":{"min":18.5,"max":23.9},"eta":240,"currency":"BRL"},{"category":"top99","price":{"min":27,"max":34.2},"eta":420,"currency":"BRL"}]`),
+		[]byte(`[{"category":"regular-taxi","price":{"min":30,"max":38},"eta":600,"currency":"BRL"}]`),
+	}, func(c *Client) (interface{}, error) {
+		return c.Estimate.Find(context.Background(), EstimateRequest{})
+	})
+
+	testRequestBody(t, []func(*Client) ([]byte, error){
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"origin":{"latitude":-23.5648,"longitude":-46.6519},"destination":{"latitude":-23.5534,"longitude":-46.6559}}`)
+			_, err = c.Estimate.Find(context.Background(), EstimateRequest{
+				Origin:      &Location{Latitude: -23.5648, Longitude: -46.6519},
+				Destination: &Location{Latitude: -23.5534, Longitude: -46.6559},
+			})
+			return
+		},
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"origin":{"latitude":-23.5648,"longitude":-46.6519},"destination":{"latitude":-23.5534,"longitude":-46.6559},"costCenterId":77045,"employeeId":125}`)
+			_, err = c.Estimate.Find(context.Background(), EstimateRequest{
+				Origin:       &Location{Latitude: -23.5648, Longitude: -46.6519},
+				Destination:  &Location{Latitude: -23.5534, Longitude: -46.6559},
+				CostCenterID: 77045,
+				EmployeeID:   125,
+			})
+			return
+		},
+	})
+}
+
+func TestEstimateFindError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.Estimate.Find(context.Background(), EstimateRequest{})
+		return err
+	})
+}
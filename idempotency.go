@@ -0,0 +1,78 @@
+package taxis99
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+const headerIdempotencyKey = "Idempotency-Key"
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a context whose POST requests send the
+// key in the Idempotency-Key header, so the API applies them once
+// even if they are retried or called again with the context. The key
+// identifies a single operation: distinct POST requests sharing it are
+// answered with the response of the first one. EmployeeService.Import
+// derives a key per row from it.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// idempotencyKeyFrom returns the key of the context, if any.
+func idempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+// NewIdempotencyKey returns a random UUID to be used as idempotency key.
+func NewIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("taxis99: reading random idempotency key: " + err.Error())
+	}
+
+	// Sets the version 4 and the RFC 4122 variant.
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// requestIdempotencyKey returns the key of the request
+// from the context, generating one if the Client does.
+func (c *Client) requestIdempotencyKey(ctx context.Context) string {
+	if key := idempotencyKeyFrom(ctx); key != "" {
+		return key
+	}
+	if c.IdempotencyKeys {
+		return NewIdempotencyKey()
+	}
+	return ""
+}
+
+type dedupeKey struct{}
+
+// lookupFunc looks up the entity a create request would create,
+// filling the call output if it's found.
+type lookupFunc func(ctx context.Context) (bool, error)
+
+// withDedupe returns a context whose create request is not sent
+// again if the lookup finds the entity after an ambiguous failure.
+func withDedupe(ctx context.Context, fn lookupFunc) context.Context {
+	return context.WithValue(ctx, dedupeKey{}, fn)
+}
+
+// dedupe looks up the entity of a create request retried after an
+// ambiguous failure, like a timeout or a server error, as the first
+// attempt may have been applied.
+func dedupe(ctx context.Context) (bool, error) {
+	fn, _ := ctx.Value(dedupeKey{}).(lookupFunc)
+	if fn == nil {
+		return false, nil
+	}
+
+	// The lookup requests must not be deduplicated themselves.
+	return fn(withDedupe(ctx, nil))
+}
@@ -0,0 +1,293 @@
+package taxis99
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewIdempotencyKey(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	a, b := NewIdempotencyKey(), NewIdempotencyKey()
+	if !uuid.MatchString(a) {
+		t.Errorf("Got key '%s'; want a version 4 UUID.", a)
+	}
+	if a == b {
+		t.Errorf("Got the same key '%s' twice; want random keys.", a)
+	}
+}
+
+func TestIdempotencyKeyHeader(t *testing.T) {
+	testCases := []struct {
+		name     string
+		method   string
+		generate bool
+		key      string
+		want     string
+	}{
+		{"CallerKey", http.MethodPost, false, "key-1", "key-1"},
+		{"CallerKeyGenerating", http.MethodPost, true, "key-1", "key-1"},
+		{"NoKey", http.MethodPost, false, "", ""},
+		{"Generated", http.MethodPost, true, "", "generated"},
+		{"NotPost", http.MethodPut, true, "key-1", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get(headerIdempotencyKey)
+			})
+			defer srv.Close()
+
+			client.IdempotencyKeys = tc.generate
+
+			ctx := context.Background()
+			if tc.key != "" {
+				ctx = WithIdempotencyKey(ctx, tc.key)
+			}
+			client.Request(ctx, tc.method, "costcenters", CostCenter{}, nil)
+
+			if tc.want == "generated" {
+				if len(got) != 36 {
+					t.Errorf("Got Idempotency-Key '%s'; want a generated UUID.", got)
+				}
+				return
+			}
+			if got != tc.want {
+				t.Errorf("Got Idempotency-Key '%s'; want '%s'.", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyRetry(t *testing.T) {
+	var keys []string
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`[]`))
+			return
+		}
+		keys = append(keys, r.Header.Get(headerIdempotencyKey))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1,"name":"IT"}`))
+	})
+	defer srv.Close()
+
+	client.Retry = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	client.IdempotencyKeys = true
+
+	cc, err := client.CostCenter.Create(context.Background(), CostCenter{Name: "IT"})
+	if err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+	if cc.ID != 1 {
+		t.Errorf("Got cost center %+v; want the created one.", cc)
+	}
+
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Got keys %q; want the same key in both attempts.", keys)
+	}
+}
+
+func TestCreateDedupe(t *testing.T) {
+	testCases := []struct {
+		name      string
+		lookup    func(w http.ResponseWriter, r *http.Request)
+		create    func(c *Client) (int64, error)
+		wantPosts int
+		wantID    int64
+	}{
+		{
+			"CostCenterFound",
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("search") != "IT" {
+					t.Errorf("Got search '%s'; want 'IT'.", r.URL.Query().Get("search"))
+				}
+				w.Write([]byte(`[{"id":7,"name":"IT Support"},{"id":5,"name":"it"}]`))
+			},
+			func(c *Client) (int64, error) {
+				cc, err := c.CostCenter.Create(context.Background(), CostCenter{Name: "IT"})
+				if err != nil {
+					return 0, err
+				}
+				return cc.ID, nil
+			},
+			1, 5,
+		},
+		{
+			"CostCenterNotFound",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`[{"id":7,"name":"IT Support"}]`))
+			},
+			func(c *Client) (int64, error) {
+				cc, err := c.CostCenter.Create(context.Background(), CostCenter{Name: "IT"})
+				if err != nil {
+					return 0, err
+				}
+				return cc.ID, nil
+			},
+			2, 1,
+		},
+		{
+			"EmployeeFound",
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/employees/external-id/42" {
+					t.Errorf("Got lookup path '%s'; want '/employees/external-id/42'.", r.URL.Path)
+				}
+				w.Write([]byte(`[{"id":9,"externalId":42}]`))
+			},
+			func(c *Client) (int64, error) {
+				emp, err := c.Employee.Create(context.Background(), Employee{ExternalID: 42}, false)
+				if err != nil {
+					return 0, err
+				}
+				return emp.ID, nil
+			},
+			1, 9,
+		},
+		{
+			"EmployeeNotFound",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			func(c *Client) (int64, error) {
+				emp, err := c.Employee.Create(context.Background(), Employee{ExternalID: 42}, false)
+				if err != nil {
+					return 0, err
+				}
+				return emp.ID, nil
+			},
+			2, 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var posts int
+			client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					tc.lookup(w, r)
+					return
+				}
+				posts++
+				if posts == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id":1}`))
+			})
+			defer srv.Close()
+
+			client.Retry = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, RetryNonIdempotent: true}
+
+			id, err := tc.create(client)
+			if err != nil {
+				t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+			}
+			if id != tc.wantID {
+				t.Errorf("Got ID %d; want %d.", id, tc.wantID)
+			}
+			if posts != tc.wantPosts {
+				t.Errorf("Got %d POST requests; want %d.", posts, tc.wantPosts)
+			}
+		})
+	}
+}
+
+func TestCreateDedupeLookupError(t *testing.T) {
+	var posts int
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts++
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer srv.Close()
+
+	client.Retry = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryNonIdempotent: true}
+
+	_, err := client.CostCenter.Create(context.Background(), CostCenter{Name: "IT"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Got error '%v'; want the 503 of the create request.", err)
+	}
+	if posts != 1 {
+		t.Errorf("Got %d POST requests; want 1 since the lookup failed.", posts)
+	}
+}
+
+func TestCreateNoDedupeOnClientError(t *testing.T) {
+	var gets int
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets++
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer srv.Close()
+
+	client.Retry = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, RetryNonIdempotent: true}
+
+	client.CostCenter.Create(context.Background(), CostCenter{Name: "IT"})
+
+	if gets != 0 {
+		t.Errorf("Got %d lookups after a 429; want none.", gets)
+	}
+}
+
+func TestIdempotencyKeyRepeatedCall(t *testing.T) {
+	var keys []string
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			keys = append(keys, r.Header.Get(headerIdempotencyKey))
+		}
+	})
+	defer srv.Close()
+
+	client.IdempotencyKeys = true
+
+	// Like a create called again after it timed out.
+	ctx := WithIdempotencyKey(context.Background(), "key-1")
+	client.CostCenter.Create(ctx, CostCenter{Name: "IT"})
+	client.CostCenter.Create(ctx, CostCenter{Name: "IT"})
+
+	if len(keys) != 2 || keys[0] != "key-1" || keys[1] != "key-1" {
+		t.Errorf("Got keys %q; want the context key in both calls.", keys)
+	}
+}
+
+func TestIdempotencyKeyImport(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		keys = map[string]bool{}
+	)
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys[r.Header.Get(headerIdempotencyKey)] = true
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer srv.Close()
+
+	ctx := WithIdempotencyKey(context.Background(), "key-1")
+	emps := []Employee{{Name: "Jose"}, {Name: "Maria"}, {Name: "Joao"}}
+	client.Employee.Import(ctx, emps, ImportOptions{})
+
+	want := map[string]bool{"key-1-0": true, "key-1-1": true, "key-1-2": true}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("Got keys %v; want %v.", keys, want)
+	}
+}
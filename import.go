@@ -0,0 +1,178 @@
+package taxis99
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const defaultImportConcurrency = 4
+
+// ImportOptions configures the bulk import of employees.
+type ImportOptions struct {
+	// Concurrency is the maximum number of employees created
+	// at the same time. Defaults to 4. Requests still go
+	// through the Client RateLimiter.
+	Concurrency int
+
+	// SendWelcomeEmail sends the welcome email to the
+	// created employees.
+	SendWelcomeEmail bool
+}
+
+// ImportResult is the result of importing one employee.
+type ImportResult struct {
+	// Index is the position of the employee in the input.
+	Index    int
+	Employee Employee
+
+	// Created is the created employee, if there was no error.
+	Created *Employee
+	Err     error
+}
+
+// ValidationError returns the fields rejected by the API, if any.
+func (r ImportResult) ValidationError() *ValidationError {
+	var valErr *ValidationError
+	if errors.As(r.Err, &valErr) {
+		return valErr
+	}
+	return nil
+}
+
+// ImportReport has the results of a bulk import ordered by index.
+type ImportReport struct {
+	Results []ImportResult
+}
+
+// Created returns the employees created.
+func (r *ImportReport) Created() []*Employee {
+	var emps []*Employee
+	for _, res := range r.Results {
+		if res.Err == nil {
+			emps = append(emps, res.Created)
+		}
+	}
+	return emps
+}
+
+// Failed returns the results of the employees not created.
+func (r *ImportReport) Failed() []ImportResult {
+	var failed []ImportResult
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Import creates the employees concurrently. A failure doesn't stop
+// the import: the report has the created employee or the error of
+// every row. Rows not sent once the context is done fail with its error.
+//
+// An idempotency key of the context is sent as "<key>-<index>" by
+// each row, so running the import again with the same key doesn't
+// create the employees twice.
+func (e *EmployeeService) Import(ctx context.Context, emps []Employee, opts ImportOptions) *ImportReport {
+	ch := make(chan Employee)
+	go func() {
+		defer close(ch)
+		for _, emp := range emps {
+			select {
+			case ch <- emp:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	report := e.ImportFrom(ctx, ch, opts)
+
+	// The rows never received once the context is done.
+	for i := len(report.Results); i < len(emps); i++ {
+		report.Results = append(report.Results, ImportResult{Index: i, Employee: emps[i], Err: ctx.Err()})
+	}
+
+	return report
+}
+
+// ImportFrom creates the employees received from the channel
+// concurrently, until it's closed or the context is done. Rows
+// received but not sent once the context is done fail with its
+// error; the ones left in the channel are not received. See Import.
+func (e *EmployeeService) ImportFrom(ctx context.Context, emps <-chan Employee, opts ImportOptions) *ImportReport {
+	n := opts.Concurrency
+	if n <= 0 {
+		n = defaultImportConcurrency
+	}
+
+	type row struct {
+		index int
+		emp   Employee
+	}
+
+	// A key of the context is the base of the key of each row,
+	// as the same key would replay the first row for all of them.
+	key := idempotencyKeyFrom(ctx)
+
+	var (
+		mu     sync.Mutex
+		report = new(ImportReport)
+		wg     sync.WaitGroup
+	)
+
+	rows := make(chan row)
+	go func() {
+		defer close(rows)
+		for i := 0; ctx.Err() == nil; i++ {
+			var emp Employee
+			select {
+			case e, ok := <-emps:
+				if !ok {
+					return
+				}
+				emp = e
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case rows <- row{i, emp}:
+			case <-ctx.Done():
+				mu.Lock()
+				report.Results = append(report.Results, ImportResult{Index: i, Employee: emp, Err: ctx.Err()})
+				mu.Unlock()
+				return
+			}
+		}
+	}()
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range rows {
+				rowCtx := ctx
+				if key != "" {
+					rowCtx = WithIdempotencyKey(ctx, fmt.Sprintf("%s-%d", key, r.index))
+				}
+
+				res := ImportResult{Index: r.index, Employee: r.emp}
+				res.Created, res.Err = e.Create(rowCtx, r.emp, opts.SendWelcomeEmail)
+
+				mu.Lock()
+				report.Results = append(report.Results, res)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(report.Results, func(i, j int) bool {
+		return report.Results[i].Index < report.Results[j].Index
+	})
+
+	return report
+}
@@ -0,0 +1,233 @@
+package taxis99
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEmployeeImport(t *testing.T) {
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		var req reqEmployee
+		json.NewDecoder(r.Body).Decode(&req)
+
+		switch {
+		case strings.HasPrefix(req.Employee.Email, "invalid"):
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"errors":[{"code":"error.invalidEmail","field":"employee.email","message":"error.invalidEmail"}]}`))
+		case strings.HasPrefix(req.Employee.Email, "fail"):
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":%d,"email":%q}`, req.Employee.ExternalID*10, req.Employee.Email)
+		}
+	})
+	defer srv.Close()
+
+	emps := []Employee{
+		{Email: "jose@example.com", ExternalID: 1},
+		{Email: "invalid", ExternalID: 2},
+		{Email: "maria@example.com", ExternalID: 3},
+		{Email: "fail@example.com", ExternalID: 4},
+		{Email: "joao@example.com", ExternalID: 5},
+	}
+
+	report := client.Employee.Import(context.Background(), emps, ImportOptions{Concurrency: 2})
+
+	if len(report.Results) != len(emps) {
+		t.Fatalf("Got %d results; want %d.", len(report.Results), len(emps))
+	}
+
+	for i, res := range report.Results {
+		if res.Index != i || res.Employee.ExternalID != emps[i].ExternalID {
+			t.Errorf("Got result %d for index %d employee %+v; want ordered by index.", i, res.Index, res.Employee)
+		}
+	}
+
+	if res := report.Results[1]; res.ValidationError() == nil {
+		t.Errorf("Got error '%v' for the invalid row; want ValidationError.", res.Err)
+	}
+
+	if res := report.Results[3]; res.Err == nil || res.ValidationError() != nil {
+		t.Errorf("Got error '%v' for the failed row; want a non validation error.", res.Err)
+	}
+
+	created := report.Created()
+	if len(created) != 3 || created[0].ID != 10 || created[1].ID != 30 || created[2].ID != 50 {
+		t.Errorf("Got created employees %+v; want IDs 10, 30 and 50.", created)
+	}
+
+	if failed := report.Failed(); len(failed) != 2 || failed[0].Index != 1 || failed[1].Index != 3 {
+		t.Errorf("Got failed results %+v; want indexes 1 and 3.", failed)
+	}
+}
+
+func TestEmployeeImportConcurrency(t *testing.T) {
+	var (
+		mu            sync.Mutex
+		running, peak int
+	)
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer srv.Close()
+
+	report := client.Employee.Import(context.Background(), make([]Employee, 12), ImportOptions{Concurrency: 3})
+
+	if n := len(report.Created()); n != 12 {
+		t.Errorf("Got %d employees created; want 12.", n)
+	}
+
+	if peak > 3 {
+		t.Errorf("Got %d concurrent creates; want at most 3.", peak)
+	}
+}
+
+func TestEmployeeImportRateLimiter(t *testing.T) {
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer srv.Close()
+
+	client.RateLimiter = NewRateLimiter(100, 1)
+
+	start := time.Now()
+	client.Employee.Import(context.Background(), make([]Employee, 5), ImportOptions{Concurrency: 5})
+
+	// The first request uses the burst, the other 4 wait 10ms each.
+	if d := time.Since(start); d < 35*time.Millisecond {
+		t.Errorf("Got import in %s; want the rate limiter to space the requests.", d)
+	}
+}
+
+func TestEmployeeImportFrom(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent []bool
+	)
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		var req reqEmployee
+		json.NewDecoder(r.Body).Decode(&req)
+
+		mu.Lock()
+		sent = append(sent, req.SendWelcomeEmail)
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	})
+	defer srv.Close()
+
+	ch := make(chan Employee)
+	go func() {
+		defer close(ch)
+		for i := 0; i < 3; i++ {
+			ch <- Employee{ExternalID: int64(i)}
+		}
+	}()
+
+	report := client.Employee.ImportFrom(context.Background(), ch, ImportOptions{SendWelcomeEmail: true})
+
+	if len(report.Results) != 3 || len(report.Failed()) != 0 {
+		t.Errorf("Got results %+v; want 3 created.", report.Results)
+	}
+
+	for _, s := range sent {
+		if !s {
+			t.Error("Got sendWelcomeEmail false; want true.")
+		}
+	}
+}
+
+func TestEmployeeImportContextCancelled(t *testing.T) {
+	var called bool
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report := client.Employee.Import(ctx, make([]Employee, 3), ImportOptions{})
+
+	if len(report.Results) != 3 {
+		t.Fatalf("Got %d results; want 3.", len(report.Results))
+	}
+	for _, res := range report.Results {
+		if !errors.Is(res.Err, context.Canceled) {
+			t.Errorf("Got error '%v' for row %d; want context.Canceled.", res.Err, res.Index)
+		}
+	}
+
+	if called {
+		t.Error("Got the API called; want it not called.")
+	}
+}
+
+func TestEmployeeImportFromContextCancelled(t *testing.T) {
+	block := make(chan struct{})
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	})
+	defer srv.Close()
+	defer close(block)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The channel is never closed, like a stream still being read.
+	ch := make(chan Employee)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case ch <- Employee{ExternalID: int64(i)}:
+			case <-time.After(time.Second):
+				return
+			}
+		}
+	}()
+
+	done := make(chan *ImportReport)
+	go func() {
+		done <- client.Employee.ImportFrom(ctx, ch, ImportOptions{Concurrency: 2})
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	var report *ImportReport
+	select {
+	case report = <-done:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("Got ImportFrom still running after the context was cancelled; want it to return.")
+	}
+
+	// 2 rows were being created and 1 was waiting for a worker.
+	if len(report.Results) != 3 {
+		t.Fatalf("Got %d results; want 3.", len(report.Results))
+	}
+	for i, res := range report.Results {
+		if res.Index != i || !errors.Is(res.Err, context.Canceled) {
+			t.Errorf("Got result %d for index %d with error '%v'; want context.Canceled.", i, res.Index, res.Err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package taxis99
+
+import (
+	"context"
+	"strconv"
+)
+
+// pager walks through the pages of a Find call. It stops
+// when the API returns an empty page or a page shorter than
+// the filter limit.
+type pager struct {
+	ctx   context.Context
+	f     Filter
+	page  int
+	limit int
+	done  bool
+	err   error
+
+	// fetch loads the page described by the filter
+	// and returns the number of items found.
+	fetch func(ctx context.Context, f Filter) (int, error)
+}
+
+func newPager(ctx context.Context, f Filter, fetch func(context.Context, Filter) (int, error)) *pager {
+	// Copy the filter so the caller's one is not modified.
+	pf := make(Filter, len(f))
+	for k, v := range f {
+		pf[k] = v
+	}
+
+	page, _ := strconv.Atoi(pf["page"])
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(pf["limit"])
+
+	return &pager{
+		ctx:   ctx,
+		f:     pf,
+		page:  page,
+		limit: limit,
+		fetch: fetch,
+	}
+}
+
+// next fetches the next page. It returns false when
+// there are no more pages or an error happened.
+func (p *pager) next() bool {
+	if p.done || p.err != nil {
+		return false
+	}
+
+	if p.checkContext() {
+		return false
+	}
+
+	p.f.Set("page", strconv.Itoa(p.page))
+
+	n, err := p.fetch(p.ctx, p.f)
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	p.page++
+	if n == 0 || (p.limit > 0 && n < p.limit) {
+		p.done = true
+	}
+
+	return n > 0
+}
+
+// checkContext reports whether the context is done,
+// keeping its error.
+func (p *pager) checkContext() bool {
+	if p.err != nil {
+		return true
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return true
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package taxis99
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// pagesRequester returns a mockRequester that answers each page
+// with the given JSON responses, recording the requested pages.
+func pagesRequester(pages []string, requested *[]string) mockRequester {
+	return func(ctx context.Context, method, path string, body, output interface{}) error {
+		u, _ := url.Parse(path)
+		page := u.Query().Get("page")
+		*requested = append(*requested, page)
+
+		var n int
+		fmt.Sscan(page, &n)
+		if n < 1 || n > len(pages) {
+			return json.Unmarshal([]byte(`[]`), output)
+		}
+		return json.Unmarshal([]byte(pages[n-1]), output)
+	}
+}
+
+func TestEmployeeAll(t *testing.T) {
+	testCases := []struct {
+		name      string
+		filter    Filter
+		pages     []string
+		wantIDs   []int64
+		wantPages []string
+	}{
+		{
+			"EmptyPage",
+			nil,
+			[]string{`[{"id":1},{"id":2}]`, `[{"id":3}]`},
+			[]int64{1, 2, 3},
+			[]string{"1", "2", "3"},
+		},
+		{
+			"ShortPage",
+			Filter{"limit": "2"},
+			[]string{`[{"id":1},{"id":2}]`, `[{"id":3}]`, `[{"id":4}]`},
+			[]int64{1, 2, 3},
+			[]string{"1", "2"},
+		},
+		{
+			"StartPage",
+			Filter{"limit": "1", "page": "2"},
+			[]string{`[{"id":1}]`, `[{"id":2}]`, `[{"id":3}]`},
+			[]int64{2, 3},
+			[]string{"2", "3", "4"},
+		},
+		{
+			"NoResults",
+			nil,
+			nil,
+			nil,
+			[]string{"1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pages []string
+			c := newMockRequesterClient(pagesRequester(tc.pages, &pages))
+
+			var got []int64
+			it := c.Employee.All(context.Background(), tc.filter)
+			for it.Next() {
+				got = append(got, it.Employee().ID)
+			}
+
+			if err := it.Err(); err != nil {
+				t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+			}
+
+			if !reflect.DeepEqual(got, tc.wantIDs) {
+				t.Errorf("Got employee IDs %v; want %v.", got, tc.wantIDs)
+			}
+
+			if !reflect.DeepEqual(pages, tc.wantPages) {
+				t.Errorf("Got requested pages %v; want %v.", pages, tc.wantPages)
+			}
+		})
+	}
+}
+
+func TestEmployeeAllFilterNotModified(t *testing.T) {
+	var pages []string
+	c := newMockRequesterClient(pagesRequester([]string{`[{"id":1}]`}, &pages))
+
+	f := Filter{"search": "José"}
+	it := c.Employee.All(context.Background(), f)
+	for it.Next() {
+	}
+
+	if want := (Filter{"search": "José"}); !reflect.DeepEqual(f, want) {
+		t.Errorf("Got Filter: %+v; want %+v.", f, want)
+	}
+}
+
+func TestEmployeeAllError(t *testing.T) {
+	t.Run("Request", func(t *testing.T) {
+		c := newMockRequesterClient(func(ctx context.Context, method, path string, body, output interface{}) error {
+			return errors.New("Error!")
+		})
+
+		it := c.Employee.All(context.Background(), nil)
+		if it.Next() {
+			t.Error("Got Next true; want false.")
+		}
+		if it.Err() == nil {
+			t.Error("Got error nil; want it not nil.")
+		}
+	})
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var pages []string
+		c := newMockRequesterClient(pagesRequester([]string{`[{"id":1},{"id":2}]`, `[{"id":3}]`}, &pages))
+
+		it := c.Employee.All(ctx, nil)
+		if !it.Next() {
+			t.Fatalf("Got Next false; want true.")
+		}
+		cancel()
+
+		if it.Next() {
+			t.Error("Got Next true after cancel; want false.")
+		}
+		if !errors.Is(it.Err(), context.Canceled) {
+			t.Errorf("Got error '%v'; want context.Canceled.", it.Err())
+		}
+	})
+}
+
+func TestEmployeeEach(t *testing.T) {
+	var pages []string
+	c := newMockRequesterClient(pagesRequester([]string{`[{"id":1},{"id":2}]`, `[{"id":3}]`}, &pages))
+
+	var got []int64
+	err := c.Employee.Each(context.Background(), nil, func(e *Employee) error {
+		got = append(got, e.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got employee IDs %v; want %v.", got, want)
+	}
+}
+
+func TestEmployeeEachError(t *testing.T) {
+	var pages []string
+	c := newMockRequesterClient(pagesRequester([]string{`[{"id":1},{"id":2}]`}, &pages))
+
+	want := errors.New("stop")
+	var calls int
+	err := c.Employee.Each(context.Background(), nil, func(e *Employee) error {
+		calls++
+		return want
+	})
+	if err != want {
+		t.Errorf("Got error '%v'; want '%v'.", err, want)
+	}
+	if calls != 1 {
+		t.Errorf("Got %d calls; want 1.", calls)
+	}
+}
+
+func TestCostCenterAll(t *testing.T) {
+	var pages []string
+	c := newMockRequesterClient(pagesRequester([]string{`[{"id":1},{"id":2}]`, `[{"id":3}]`}, &pages))
+
+	var got []int64
+	err := c.CostCenter.Each(context.Background(), Filter{"limit": "2"}, func(cc *CostCenter) error {
+		got = append(got, cc.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got cost center IDs %v; want %v.", got, want)
+	}
+}
+
+func TestRideAll(t *testing.T) {
+	var pages []string
+	c := newMockRequesterClient(pagesRequester([]string{`[{"id":1},{"id":2}]`, `[{"id":3}]`}, &pages))
+
+	var got []int64
+	it := c.Ride.All(context.Background(), Filter{"limit": "2", "status": "FINISHED"})
+	for it.Next() {
+		got = append(got, it.Ride().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got ride IDs %v; want %v.", got, want)
+	}
+
+	if got := strings.Join(pages, ","); got != "1,2" {
+		t.Errorf("Got requested pages %s; want 1,2.", got)
+	}
+}
@@ -0,0 +1,177 @@
+package taxis99
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// redactedHeaders are never logged nor recorded.
+var redactedHeaders = []string{headerAPIKey, "Authorization"}
+
+// redactedFields are the JSON fields with personal data.
+var redactedFields = map[string]struct{}{
+	"email":      struct{}{},
+	"phone":      struct{}{},
+	"nationalid": struct{}{},
+}
+
+// redactedParams are the query params with personal data.
+var redactedParams = []string{"search", "nationalId", "email"}
+
+// LogEntry is a structured log entry of a request.
+type LogEntry struct {
+	// Operation is the service operation, like Employee.Create.
+	Operation  string
+	Method     string
+	Path       string
+	StatusCode int
+	Latency    time.Duration
+	// Attempt is the attempt number, starting at 1. It's
+	// zero for entries logged by the Transport.
+	Attempt   int
+	CompanyID string
+	Err       error
+
+	// Header and Body are the request header and body with the API key
+	// and personal data redacted. Only logged by the Transport.
+	Header http.Header
+	Body   []byte
+}
+
+// Logger receives the log entries of the Client and Transport.
+type Logger interface {
+	Log(ctx context.Context, e LogEntry)
+}
+
+// LoggerFunc is an adapter to use ordinary functions as Logger.
+type LoggerFunc func(ctx context.Context, e LogEntry)
+
+func (fn LoggerFunc) Log(ctx context.Context, e LogEntry) {
+	fn(ctx, e)
+}
+
+// NewStdLogger returns a Logger writing the entries as key=value
+// pairs to l, or to the standard logger if l is nil.
+func NewStdLogger(l *log.Logger) Logger {
+	return LoggerFunc(func(ctx context.Context, e LogEntry) {
+		var b strings.Builder
+		b.WriteString("taxis99:")
+		if e.Operation != "" {
+			fmt.Fprintf(&b, " op=%s", e.Operation)
+		}
+		fmt.Fprintf(&b, " method=%s path=%s status=%d latency=%s", e.Method, e.Path, e.StatusCode, e.Latency)
+		if e.Attempt > 0 {
+			fmt.Fprintf(&b, " attempt=%d", e.Attempt)
+		}
+		if e.CompanyID != "" {
+			fmt.Fprintf(&b, " company=%s", e.CompanyID)
+		}
+		if e.Err != nil {
+			fmt.Fprintf(&b, " err=%q", e.Err.Error())
+		}
+		for k, v := range e.Header {
+			fmt.Fprintf(&b, " header.%s=%q", k, strings.Join(v, ","))
+		}
+		if len(e.Body) > 0 {
+			fmt.Fprintf(&b, " body=%s", e.Body)
+		}
+
+		if l == nil {
+			log.Print(b.String())
+			return
+		}
+		l.Print(b.String())
+	})
+}
+
+// redactHeader returns a copy of the header with the secrets redacted.
+func redactHeader(h http.Header) http.Header {
+	c := h.Clone()
+	for _, k := range redactedHeaders {
+		if c.Get(k) != "" {
+			c.Set(k, redacted)
+		}
+	}
+	return c
+}
+
+// redactPath returns the path with the personal
+// data in the query params redacted.
+func redactPath(path string) string {
+	u, err := url.Parse(path)
+	if err != nil {
+		return redacted
+	}
+
+	q := u.Query()
+	if len(q) == 0 {
+		return path
+	}
+	for _, k := range redactedParams {
+		if _, ok := q[k]; ok {
+			q.Set(k, redacted)
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// redactError returns the transport errors with the personal
+// data in their URL redacted, as they repeat the request URL.
+func redactError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+
+	c := *urlErr
+	c.URL = redactPath(urlErr.URL)
+	return &c
+}
+
+// redactBody returns the JSON body with the personal data
+// redacted. Bodies that are not JSON are fully redacted.
+func redactBody(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return []byte(redacted)
+	}
+
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return []byte(redacted)
+	}
+	return b
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range v {
+			if _, ok := redactedFields[strings.ToLower(k)]; ok {
+				v[k] = redacted
+				continue
+			}
+			v[k] = redactValue(fv)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = redactValue(e)
+		}
+	}
+	return v
+}
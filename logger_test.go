@@ -0,0 +1,211 @@
+package taxis99
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientLogger(t *testing.T) {
+	var attempts int
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}
+
+	hc := &http.Client{Transport: &Transport{Key: "secret-key", CompanyID: "abc"}}
+	client, srv := newMockServer(hc, handler)
+	defer srv.Close()
+
+	client.Retry = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+
+	var entries []LogEntry
+	client.Logger = LoggerFunc(func(ctx context.Context, e LogEntry) {
+		entries = append(entries, e)
+	})
+
+	_, err := client.Employee.Find(context.Background(), Filter{"search": "jose@empresa.com.br", "limit": "10"})
+	if err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Got %d log entries; want 2.", len(entries))
+	}
+
+	for i, e := range entries {
+		if e.Operation != "Employee.Find" || e.Method != http.MethodGet || e.CompanyID != "abc" {
+			t.Errorf("Got log entry %+v; want Employee.Find GET for company abc.", e)
+		}
+
+		if e.Attempt != i+1 {
+			t.Errorf("Got attempt %d; want %d.", e.Attempt, i+1)
+		}
+
+		if strings.Contains(e.Path, "jose") {
+			t.Errorf("Got path '%s'; want the search redacted.", e.Path)
+		}
+	}
+
+	if entries[0].StatusCode != http.StatusServiceUnavailable || entries[1].StatusCode != http.StatusOK {
+		t.Errorf("Got status codes %d and %d; want 503 and 200.", entries[0].StatusCode, entries[1].StatusCode)
+	}
+}
+
+func TestClientLoggerTransportError(t *testing.T) {
+	tripper := testRoundTripperFn(func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("Testing error.")
+	})
+
+	var out bytes.Buffer
+	client := NewClient(&http.Client{Transport: tripper})
+	client.Logger = NewStdLogger(log.New(&out, "", 0))
+
+	f := Filter{"search": "jose@empresa.com.br", "nationalId": "12345678900"}
+	_, err := client.Employee.Find(context.Background(), f)
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Fatalf("Got error %v; want *url.Error.", err)
+	}
+
+	logged := out.String()
+	for _, secret := range []string{"jose@empresa.com.br", "12345678900"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("Got '%s' in the log %s; want it redacted.", secret, logged)
+		}
+	}
+
+	if !strings.Contains(logged, "Testing error.") {
+		t.Errorf("Got log %s; want it to contain the error.", logged)
+	}
+}
+
+func TestClientLoggerCompanyIDContext(t *testing.T) {
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {})
+	defer srv.Close()
+
+	var got string
+	client.Logger = LoggerFunc(func(ctx context.Context, e LogEntry) {
+		got = e.CompanyID
+	})
+
+	ctx := context.WithValue(context.Background(), CompanyID, "123")
+	client.Company.Find(ctx)
+
+	if got != "123" {
+		t.Errorf("Got company ID '%s'; want '123'.", got)
+	}
+}
+
+func TestTransportLogger(t *testing.T) {
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := new(bytes.Buffer)
+		b.ReadFrom(r.Body)
+		gotBody = b.String()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	client, err := New(WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("Got error calling New: %s; want it to be nil.", err.Error())
+	}
+	client.client.Transport = &Transport{
+		Key:       "secret-key",
+		CompanyID: "abc",
+		Logger:    NewStdLogger(log.New(&out, "", 0)),
+	}
+
+	emp := Employee{
+		Name:       "José Santos",
+		Email:      "jose.santos@empresa.com.br",
+		Phone:      &Phone{Number: "11999999999", Country: "BRA"},
+		NationalID: "98765432100",
+	}
+	if _, err := client.Employee.Create(context.Background(), emp, false); err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	if !strings.Contains(gotBody, emp.Email) {
+		t.Errorf("Got request body %s sent to the server; want it not redacted.", gotBody)
+	}
+
+	logged := out.String()
+	for _, secret := range []string{"secret-key", emp.Email, "11999999999", emp.NationalID} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("Got '%s' in the log %s; want it redacted.", secret, logged)
+		}
+	}
+
+	for _, want := range []string{"method=POST", "path=/employees", "status=201", "company=abc", "José Santos"} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("Got log %s; want it to contain '%s'.", logged, want)
+		}
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	testCases := []struct {
+		body string
+		want string
+	}{
+		{`{"employee":{"name":"José","email":"jose@empresa.com.br","phone":{"number":"11999999999"},"nationalId":"987"}}`, `{"employee":{"email":"[REDACTED]","name":"José","nationalId":"[REDACTED]","phone":"[REDACTED]"}}`},
+		{`[{"Email":"jose@empresa.com.br"}]`, `[{"Email":"[REDACTED]"}]`},
+		{`invalid`, `[REDACTED]`},
+		{``, ``},
+	}
+
+	for _, tc := range testCases {
+		if got := string(redactBody([]byte(tc.body))); got != tc.want {
+			t.Errorf("Got redacted body %s; want %s.", got, tc.want)
+		}
+	}
+}
+
+func TestRedactPath(t *testing.T) {
+	testCases := []struct {
+		path string
+		want string
+	}{
+		{"employees", "employees"},
+		{"employees?limit=10&nationalId=987", "employees?limit=10&nationalId=%5BREDACTED%5D"},
+		{"employees?search=jose", "employees?search=%5BREDACTED%5D"},
+	}
+
+	for _, tc := range testCases {
+		if got := redactPath(tc.path); got != tc.want {
+			t.Errorf("Got redacted path %s; want %s.", got, tc.want)
+		}
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	l := NewStdLogger(nil)
+
+	c, err := New(WithLogger(l), WithAPIKey("key"))
+	if err != nil {
+		t.Fatalf("Got error calling New: %s; want it to be nil.", err.Error())
+	}
+
+	if c.Logger == nil {
+		t.Error("Got Client.Logger nil; want it to be set.")
+	}
+
+	if tr := c.client.Transport.(*Transport); tr.Logger != nil {
+		t.Error("Got Transport.Logger set; want it to be nil.")
+	}
+}
@@ -0,0 +1,307 @@
+package taxis99
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the latency histogram buckets, in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Label is a metric label.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a metric value with its labels.
+type Sample struct {
+	// Name is the sample name, which has a suffix
+	// like _bucket for histograms.
+	Name   string
+	Labels []Label
+	Value  float64
+}
+
+// MetricFamily groups the samples of a metric, following
+// the Prometheus data model.
+type MetricFamily struct {
+	Name string
+	Help string
+	// Type is either counter or histogram.
+	Type    string
+	Samples []Sample
+}
+
+// Collector returns the metrics it collects.
+type Collector interface {
+	Collect() []MetricFamily
+}
+
+// Registerer is the registry a Collector is registered on. It's
+// implemented by Registry. To register the metrics on a Prometheus
+// registry, adapt the Collector with the taxis99prom module, which
+// keeps the Prometheus dependency out of this one:
+//
+//	prometheus.MustRegister(taxis99prom.NewCollector(metrics))
+type Registerer interface {
+	Register(c Collector) error
+}
+
+// Metrics collects request totals by operation and status class,
+// latency histograms, retries and rate limiter wait time. It's
+// added to the Client as a Middleware.
+type Metrics struct {
+	buckets []float64
+
+	mu       sync.Mutex
+	requests map[[2]string]float64
+	retries  map[string]float64
+	latency  map[string]*histogram
+	wait     *histogram
+}
+
+// NewMetrics returns Metrics with the latency histogram buckets,
+// in seconds. DefaultBuckets are used if none is given.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &Metrics{
+		buckets:  buckets,
+		requests: map[[2]string]float64{},
+		retries:  map[string]float64{},
+		latency:  map[string]*histogram{},
+		wait:     newHistogram(buckets),
+	}
+}
+
+// Middleware returns the Middleware observing the calls.
+func (m *Metrics) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			start := time.Now()
+			err := next(ctx, call)
+			m.observe(call, time.Since(start), err)
+			return err
+		}
+	}
+}
+
+func (m *Metrics) observe(call *Call, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	op := call.Operation
+	m.requests[[2]string{op, statusClass(call.StatusCode, err)}]++
+
+	if call.Attempts > 1 {
+		m.retries[op] += float64(call.Attempts - 1)
+	}
+
+	h, ok := m.latency[op]
+	if !ok {
+		h = newHistogram(m.buckets)
+		m.latency[op] = h
+	}
+	h.observe(d.Seconds())
+
+	m.wait.observe(call.LimiterWait.Seconds())
+}
+
+// statusClass returns the status class, like 2xx,
+// or error if there was no response.
+func statusClass(status int, err error) string {
+	if status == 0 {
+		if err != nil {
+			return "error"
+		}
+		return "none"
+	}
+	return fmt.Sprintf("%dxx", status/100)
+}
+
+// Collect returns the collected metrics.
+func (m *Metrics) Collect() []MetricFamily {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	requests := MetricFamily{
+		Name: "taxis99_requests_total",
+		Help: "Total of API calls by operation and status class.",
+		Type: "counter",
+	}
+	for k, v := range m.requests {
+		requests.Samples = append(requests.Samples, Sample{
+			Name:   requests.Name,
+			Labels: []Label{{"operation", k[0]}, {"status_class", k[1]}},
+			Value:  v,
+		})
+	}
+	sortSamples(requests.Samples)
+
+	retries := MetricFamily{
+		Name: "taxis99_retries_total",
+		Help: "Total of retried attempts by operation.",
+		Type: "counter",
+	}
+	for op, v := range m.retries {
+		retries.Samples = append(retries.Samples, Sample{
+			Name:   retries.Name,
+			Labels: []Label{{"operation", op}},
+			Value:  v,
+		})
+	}
+	sortSamples(retries.Samples)
+
+	latency := MetricFamily{
+		Name: "taxis99_request_duration_seconds",
+		Help: "Latency of the API calls by operation, including retries.",
+		Type: "histogram",
+	}
+	ops := make([]string, 0, len(m.latency))
+	for op := range m.latency {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		latency.Samples = append(latency.Samples, m.latency[op].samples(latency.Name, Label{"operation", op})...)
+	}
+
+	wait := MetricFamily{
+		Name:    "taxis99_rate_limiter_wait_seconds",
+		Help:    "Time the API calls waited for the rate limiter.",
+		Type:    "histogram",
+		Samples: m.wait.samples("taxis99_rate_limiter_wait_seconds"),
+	}
+
+	return []MetricFamily{requests, retries, latency, wait}
+}
+
+func sortSamples(s []Sample) {
+	sort.Slice(s, func(i, j int) bool {
+		return labelsString(s[i].Labels) < labelsString(s[j].Labels)
+	})
+}
+
+type histogram struct {
+	buckets []float64
+	counts  []float64
+	sum     float64
+	count   float64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]float64, len(buckets)),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// samples returns the cumulative bucket, sum and count samples.
+func (h *histogram) samples(name string, labels ...Label) []Sample {
+	s := make([]Sample, 0, len(h.buckets)+3)
+	for i, b := range h.buckets {
+		s = append(s, Sample{
+			Name:   name + "_bucket",
+			Labels: append(append([]Label(nil), labels...), Label{"le", formatFloat(b)}),
+			Value:  h.counts[i],
+		})
+	}
+	s = append(s,
+		Sample{name + "_bucket", append(append([]Label(nil), labels...), Label{"le", "+Inf"}), h.count},
+		Sample{name + "_sum", labels, h.sum},
+		Sample{name + "_count", labels, h.count},
+	)
+	return s
+}
+
+// Registry is a Registerer exposing the metrics of its
+// collectors in the Prometheus text format.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return new(Registry)
+}
+
+// Register adds the collector to the registry. A collector
+// can only be registered once.
+func (r *Registry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rc := range r.collectors {
+		if rc == c {
+			return fmt.Errorf("taxis99: collector already registered")
+		}
+	}
+	r.collectors = append(r.collectors, c)
+	return nil
+}
+
+// WriteTo writes the metrics in the Prometheus text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	var b strings.Builder
+	for _, c := range collectors {
+		for _, f := range c.Collect() {
+			fmt.Fprintf(&b, "# HELP %s %s\n", f.Name, f.Help)
+			fmt.Fprintf(&b, "# TYPE %s %s\n", f.Name, f.Type)
+			for _, s := range f.Samples {
+				fmt.Fprintf(&b, "%s%s %s\n", s.Name, labelsString(s.Labels), formatFloat(s.Value))
+			}
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.WriteTo(w)
+}
+
+func labelsString(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(labels))
+	for i, l := range labels {
+		pairs[i] = fmt.Sprintf("%s=%q", l.Name, l.Value)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,145 @@
+package taxis99
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	var calls int
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.Method == http.MethodPost && calls == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"field":"employee.email","message":"error.invalidEmail"}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}
+
+	client, srv := newMockServer(nil, handler)
+	defer srv.Close()
+
+	client.Retry = &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, RetryNonIdempotent: true}
+
+	m := NewMetrics(0.5, 1)
+	client.Use(m.Middleware())
+
+	client.Employee.Create(context.Background(), Employee{}, false)
+	client.Employee.Find(context.Background(), nil)
+	client.Employee.Find(context.Background(), nil)
+
+	got := map[string]float64{}
+	for _, f := range m.Collect() {
+		for _, s := range f.Samples {
+			got[s.Name+labelsString(s.Labels)] = s.Value
+		}
+	}
+
+	want := map[string]float64{
+		`taxis99_requests_total{operation="Employee.Create",status_class="4xx"}`:       1,
+		`taxis99_requests_total{operation="Employee.Find",status_class="2xx"}`:         2,
+		`taxis99_retries_total{operation="Employee.Create"}`:                           1,
+		`taxis99_request_duration_seconds_count{operation="Employee.Find"}`:            2,
+		`taxis99_request_duration_seconds_bucket{operation="Employee.Find",le="+Inf"}`: 2,
+		`taxis99_request_duration_seconds_bucket{operation="Employee.Create",le="1"}`:  1,
+		`taxis99_rate_limiter_wait_seconds_count`:                                      3,
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Got %s %v; want %v.", k, got[k], v)
+		}
+	}
+}
+
+func TestMetricsStatusClass(t *testing.T) {
+	client := NewClient(nil)
+
+	m := NewMetrics()
+	client.Use(m.Middleware(), func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			return context.DeadlineExceeded
+		}
+	})
+
+	client.Company.Find(context.Background())
+
+	for _, f := range m.Collect() {
+		if f.Name != "taxis99_requests_total" {
+			continue
+		}
+		if len(f.Samples) != 1 || f.Samples[0].Labels[1].Value != "error" {
+			t.Errorf("Got samples %+v; want the error status class.", f.Samples)
+		}
+	}
+}
+
+func TestMetricsLimiterWait(t *testing.T) {
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	defer srv.Close()
+
+	client.RateLimiter = NewRateLimiter(50, 1)
+
+	var waits []time.Duration
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			err := next(ctx, call)
+			waits = append(waits, call.LimiterWait)
+			return err
+		}
+	})
+
+	client.Company.Find(context.Background())
+	client.Company.Find(context.Background())
+
+	if len(waits) != 2 || waits[1] < 10*time.Millisecond {
+		t.Errorf("Got limiter waits %v; want the second call to wait for the limiter.", waits)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	m := NewMetrics(1)
+	m.observe(&Call{Operation: "Company.Find", StatusCode: 200, Attempts: 1}, 100*time.Millisecond, nil)
+
+	r := NewRegistry()
+	if err := r.Register(m); err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+	if err := r.Register(m); err == nil {
+		t.Errorf("Got nil error registering twice; want error.")
+	}
+
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	for _, want := range []string{
+		"# TYPE taxis99_requests_total counter\n",
+		`taxis99_requests_total{operation="Company.Find",status_class="2xx"} 1` + "\n",
+		"# TYPE taxis99_request_duration_seconds histogram\n",
+		`taxis99_request_duration_seconds_bucket{operation="Company.Find",le="1"} 1` + "\n",
+		`taxis99_request_duration_seconds_sum{operation="Company.Find"} 0.1` + "\n",
+		"taxis99_rate_limiter_wait_seconds_count 1\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Got metrics:\n%s\nwant line %q.", buf.String(), want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Body.String() != buf.String() {
+		t.Errorf("Got served metrics:\n%s\nwant:\n%s", rec.Body.String(), buf.String())
+	}
+}
@@ -0,0 +1,88 @@
+package taxis99
+
+import (
+	"context"
+	"time"
+)
+
+type operationKey struct{}
+
+// withOperation returns a context carrying the service
+// operation name, like Employee.Create.
+func withOperation(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// operation returns the service operation name from the context.
+func operation(ctx context.Context) string {
+	op, _ := ctx.Value(operationKey{}).(string)
+	return op
+}
+
+// Call is an API call flowing through the middleware chain.
+type Call struct {
+	// Operation is the service operation, like Employee.Create.
+	// It's empty for calls made directly with Client.Request.
+	Operation string
+
+	Method string
+	Path   string
+	Body   interface{}
+	Output interface{}
+
+	// StatusCode is the status code of the last response,
+	// set once the call is sent. It's zero if there was no response.
+	StatusCode int
+
+	// Attempts is the number of attempts made, set once the call is sent.
+	Attempts int
+
+	// IdempotencyKey is sent in the Idempotency-Key header
+	// of POST requests, if it's not empty.
+	IdempotencyKey string
+
+	// LimiterWait is the time the call waited for the rate limiter.
+	LimiterWait time.Duration
+}
+
+// Handler sends the Call to the API.
+type Handler func(ctx context.Context, call *Call) error
+
+// Middleware wraps a Handler to run code before
+// and after the call is sent.
+type Middleware func(next Handler) Handler
+
+// Use appends middlewares to the Client chain. The first
+// middleware is the outermost one, seeing the call first.
+func (c *Client) Use(mws ...Middleware) {
+	c.middlewares = append(c.middlewares, mws...)
+}
+
+// Hooks returns a Middleware calling before prior to sending the
+// call and after once it's done, with its duration and error.
+// Both hooks are optional.
+func Hooks(before func(ctx context.Context, call *Call), after func(ctx context.Context, call *Call, d time.Duration, err error)) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			if before != nil {
+				before(ctx, call)
+			}
+
+			start := time.Now()
+			err := next(ctx, call)
+
+			if after != nil {
+				after(ctx, call, time.Since(start), err)
+			}
+			return err
+		}
+	}
+}
+
+// chain wraps the handler with the Client middlewares.
+func (c *Client) chain(h Handler) Handler {
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		h = c.middlewares[i](h)
+	}
+	return h
+}
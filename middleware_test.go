@@ -0,0 +1,173 @@
+package taxis99
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClientUse(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}
+
+	client, srv := newMockServer(nil, handler)
+	defer srv.Close()
+
+	var got []string
+	mw := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, call *Call) error {
+				got = append(got, name+" before")
+				err := next(ctx, call)
+				got = append(got, name+" after")
+				return err
+			}
+		}
+	}
+
+	client.Use(mw("first"), mw("second"))
+	client.Use(mw("third"))
+
+	if _, err := client.Employee.Create(context.Background(), Employee{}, false); err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	want := []string{
+		"first before", "second before", "third before",
+		"third after", "second after", "first after",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got middlewares order %v; want %v.", got, want)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Millisecond)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"field":"employee.email","message":"error.invalidEmail"}`))
+	}
+
+	client, srv := newMockServer(nil, handler)
+	defer srv.Close()
+
+	var before, after Call
+	var gotDuration time.Duration
+	var gotErr error
+	client.Use(Hooks(
+		func(ctx context.Context, call *Call) {
+			before = *call
+		},
+		func(ctx context.Context, call *Call, d time.Duration, err error) {
+			after = *call
+			gotDuration = d
+			gotErr = err
+		},
+	))
+
+	emp := Employee{Name: "José"}
+	_, err := client.Employee.Create(context.Background(), emp, true)
+
+	if before.Operation != "Employee.Create" || before.Method != http.MethodPost || before.Path != string(employeesEndpoint) {
+		t.Errorf("Got call before sending %+v; want Employee.Create POST employees.", before)
+	}
+
+	if body, ok := before.Body.(reqEmployee); !ok || body.Employee.Name != emp.Name || !body.SendWelcomeEmail {
+		t.Errorf("Got call body %+v; want the employee request.", before.Body)
+	}
+
+	if before.StatusCode != 0 || before.Attempts != 0 {
+		t.Errorf("Got call status %d and attempts %d before sending; want 0.", before.StatusCode, before.Attempts)
+	}
+
+	if after.StatusCode != http.StatusUnprocessableEntity || after.Attempts != 1 {
+		t.Errorf("Got call status %d and attempts %d; want 422 and 1.", after.StatusCode, after.Attempts)
+	}
+
+	if gotDuration < time.Millisecond {
+		t.Errorf("Got duration %s; want at least 1ms.", gotDuration)
+	}
+
+	if gotErr != err || !errors.As(gotErr, new(*ValidationError)) {
+		t.Errorf("Got error '%v' in hook; want '%v'.", gotErr, err)
+	}
+}
+
+func TestMiddlewareOperation(t *testing.T) {
+	for _, sc := range serviceCalls {
+		t.Run(sc.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {}
+
+			client, srv := newMockServer(nil, handler)
+			defer srv.Close()
+
+			var got string
+			client.Use(Hooks(func(ctx context.Context, call *Call) {
+				got = call.Operation
+			}, nil))
+
+			if err := sc.call(context.Background(), client); err != nil {
+				t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+			}
+
+			if got != sc.name {
+				t.Errorf("Got operation '%s'; want '%s'.", got, sc.name)
+			}
+		})
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	var called bool
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}
+
+	client, srv := newMockServer(nil, handler)
+	defer srv.Close()
+
+	want := errors.New("blocked")
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			if call.Method == http.MethodDelete {
+				return want
+			}
+			return next(ctx, call)
+		}
+	})
+
+	if err := client.Employee.Remove(context.Background(), 1); err != want {
+		t.Errorf("Got error '%v'; want '%v'.", err, want)
+	}
+
+	if called {
+		t.Error("Got request sent to the server; want it not to be sent.")
+	}
+}
+
+func TestWithMiddleware(t *testing.T) {
+	var calls int
+	mw := Hooks(func(ctx context.Context, call *Call) { calls++ }, nil)
+
+	c, err := New(WithMiddleware(mw, mw))
+	if err != nil {
+		t.Fatalf("Got error calling New: %s; want it to be nil.", err.Error())
+	}
+
+	c.client.Transport = testRoundTripperFn(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}}, nil
+	})
+
+	if err := c.Request(context.Background(), http.MethodGet, "", nil, nil); err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	if calls != 2 {
+		t.Errorf("Got %d middleware calls; want 2.", calls)
+	}
+}
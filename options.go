@@ -0,0 +1,182 @@
+package taxis99
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option configures the Client returned by New.
+type Option func(*options)
+
+type options struct {
+	hc        *http.Client
+	key       string
+	companyID string
+	baseURL   string
+	userAgent string
+	timeout   time.Duration
+	retry     *RetryPolicy
+	limiter   *RateLimiter
+	breaker   *CircuitBreaker
+	idemKeys  bool
+	mws       []Middleware
+	logger    Logger
+}
+
+// WithHTTPClient sets the HTTP client used to connect to the API.
+// Its Transport is used as the base of the Transport injecting
+// the API key.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *options) {
+		o.hc = hc
+	}
+}
+
+// WithAPIKey sets the key sent in every request.
+func WithAPIKey(key string) Option {
+	return func(o *options) {
+		o.key = key
+	}
+}
+
+// WithCompanyID sets the company sent in every request. It can
+// still be overridden per request with the CompanyID context value.
+func WithCompanyID(id string) Option {
+	return func(o *options) {
+		o.companyID = id
+	}
+}
+
+// WithBaseURL sets the host used for API requests.
+func WithBaseURL(u string) Option {
+	return func(o *options) {
+		o.baseURL = u
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent in every request.
+func WithUserAgent(ua string) Option {
+	return func(o *options) {
+		o.userAgent = ua
+	}
+}
+
+// WithTimeout sets the time limit for each HTTP request.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests.
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = p
+	}
+}
+
+// WithRateLimiter sets the limiter for the requests sent to the API.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(o *options) {
+		o.limiter = l
+	}
+}
+
+// WithMiddleware appends middlewares to the Client chain.
+// See Client.Use and Hooks.
+func WithMiddleware(mws ...Middleware) Option {
+	return func(o *options) {
+		o.mws = append(o.mws, mws...)
+	}
+}
+
+// WithTracer appends the Tracing middleware for the Tracer.
+func WithTracer(t Tracer) Option {
+	return func(o *options) {
+		o.mws = append(o.mws, Tracing(t))
+	}
+}
+
+// WithCircuitBreaker sets the CircuitBreaker of the Client.
+func WithCircuitBreaker(b *CircuitBreaker) Option {
+	return func(o *options) {
+		o.breaker = b
+	}
+}
+
+// WithIdempotencyKeys generates an idempotency key for
+// the POST requests without one.
+func WithIdempotencyKeys() Option {
+	return func(o *options) {
+		o.idemKeys = true
+	}
+}
+
+// WithMetrics appends the Metrics middleware.
+func WithMetrics(m *Metrics) Option {
+	return func(o *options) {
+		o.mws = append(o.mws, m.Middleware())
+	}
+}
+
+// WithLogger sets the Logger of the Client. The Transport built
+// by New does not log, to avoid logging each request twice.
+func WithLogger(l Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// New returns a Client configured by the options. Unlike NewClient,
+// it builds the Transport injecting the API key and company ID.
+func New(opts ...Option) (*Client, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Copy the HTTP client so the caller's one is not modified.
+	hc := new(http.Client)
+	if o.hc != nil {
+		*hc = *o.hc
+	}
+
+	if o.key != "" || o.companyID != "" {
+		hc.Transport = &Transport{
+			Key:       o.key,
+			CompanyID: o.companyID,
+			Base:      hc.Transport,
+		}
+	}
+
+	if o.timeout > 0 {
+		hc.Timeout = o.timeout
+	}
+
+	c := NewClient(hc)
+
+	if o.baseURL != "" {
+		// The base URL should always have a trailing slash.
+		if !strings.HasSuffix(o.baseURL, "/") {
+			o.baseURL += "/"
+		}
+
+		u, err := url.Parse(o.baseURL)
+		if err != nil {
+			return nil, err
+		}
+		c.BaseURL = u
+	}
+
+	c.UserAgent = o.userAgent
+	c.Retry = o.retry
+	c.RateLimiter = o.limiter
+	c.Breaker = o.breaker
+	c.IdempotencyKeys = o.idemKeys
+	c.Logger = o.logger
+	c.Use(o.mws...)
+
+	return c, nil
+}
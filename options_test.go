@@ -0,0 +1,119 @@
+package taxis99
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+	}))
+	defer srv.Close()
+
+	c, err := New(
+		WithAPIKey("x-abc-key"),
+		WithCompanyID("abc"),
+		WithBaseURL(srv.URL),
+		WithUserAgent("taxis99-test"),
+	)
+	if err != nil {
+		t.Fatalf("Got error calling New: %s; want it to be nil.", err.Error())
+	}
+
+	if err := c.Request(context.Background(), http.MethodGet, "employees", nil, nil); err != nil {
+		t.Fatalf("Got error calling Request: %s; want it to be nil.", err.Error())
+	}
+
+	testCases := []struct {
+		header string
+		want   string
+	}{
+		{headerAPIKey, "x-abc-key"},
+		{headerCompanyID, "abc"},
+		{"User-Agent", "taxis99-test"},
+	}
+
+	for _, tc := range testCases {
+		if h := got.Get(tc.header); h != tc.want {
+			t.Errorf("Got %s Header: '%s'; want '%s'.", tc.header, h, tc.want)
+		}
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	retry := DefaultRetryPolicy()
+	limiter := NewRateLimiter(10, 1)
+	base := &http.Client{Transport: testRoundTripper(nil)}
+
+	c, err := New(
+		WithHTTPClient(base),
+		WithAPIKey("key"),
+		WithTimeout(time.Second),
+		WithRetryPolicy(retry),
+		WithRateLimiter(limiter),
+	)
+	if err != nil {
+		t.Fatalf("Got error calling New: %s; want it to be nil.", err.Error())
+	}
+
+	if c.BaseURL.String() != defaultBaseURL {
+		t.Errorf("Got BaseURL '%s'; want '%s'.", c.BaseURL, defaultBaseURL)
+	}
+
+	if c.Retry != retry {
+		t.Errorf("Got Retry %+v; want %+v.", c.Retry, retry)
+	}
+
+	if c.RateLimiter != limiter {
+		t.Errorf("Got RateLimiter %+v; want %+v.", c.RateLimiter, limiter)
+	}
+
+	if c.client.Timeout != time.Second {
+		t.Errorf("Got Timeout %s; want 1s.", c.client.Timeout)
+	}
+
+	tr, ok := c.client.Transport.(*Transport)
+	if !ok {
+		t.Fatalf("Got Transport %T; want *Transport.", c.client.Transport)
+	}
+
+	if tr.Base == nil {
+		t.Error("Got Transport.Base nil; want the HTTP client transport.")
+	}
+
+	if base.Timeout != 0 || base.Transport == c.client.Transport {
+		t.Error("Got the HTTP client option modified; want it unchanged.")
+	}
+}
+
+func TestNewBaseURL(t *testing.T) {
+	testCases := []struct {
+		baseURL string
+		want    string
+	}{
+		{"http://localhost:8080", "http://localhost:8080/"},
+		{"http://localhost:8080/v2/", "http://localhost:8080/v2/"},
+	}
+
+	for _, tc := range testCases {
+		c, err := New(WithBaseURL(tc.baseURL))
+		if err != nil {
+			t.Fatalf("Got error calling New: %s; want it to be nil.", err.Error())
+		}
+
+		if got := c.BaseURL.String(); got != tc.want {
+			t.Errorf("Got BaseURL '%s'; want '%s'.", got, tc.want)
+		}
+	}
+}
+
+func TestNewError(t *testing.T) {
+	if _, err := New(WithBaseURL(":")); err == nil {
+		t.Error("Got error nil; want it not to be nil.")
+	}
+}
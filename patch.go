@@ -0,0 +1,336 @@
+package taxis99
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// fieldState is the state of a patch field.
+type fieldState uint8
+
+const (
+	fieldUnset fieldState = iota
+	fieldSet
+	fieldCleared
+)
+
+// patchField holds the state of a patch field. Unset fields
+// are not sent and cleared fields are sent as null.
+type patchField struct {
+	state fieldState
+}
+
+// Clear sets the field to be cleared by the patch.
+func (f *patchField) Clear() {
+	f.state = fieldCleared
+}
+
+// Unset removes the field from the patch.
+func (f *patchField) Unset() {
+	f.state = fieldUnset
+}
+
+// IsSet reports whether the field is set to a value.
+func (f patchField) IsSet() bool {
+	return f.state == fieldSet
+}
+
+// IsCleared reports whether the field is cleared.
+func (f patchField) IsCleared() bool {
+	return f.state == fieldCleared
+}
+
+func (f patchField) changed() bool {
+	return f.state != fieldUnset
+}
+
+// OptString is a string patch field.
+type OptString struct {
+	patchField
+	value string
+}
+
+// Set sets the field to the value.
+func (o *OptString) Set(v string) {
+	o.value, o.state = v, fieldSet
+}
+
+// Value returns the value and whether the field is set.
+func (o OptString) Value() (string, bool) {
+	return o.value, o.IsSet()
+}
+
+func (o OptString) MarshalJSON() ([]byte, error) {
+	if !o.IsSet() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// OptInt64 is an int64 patch field.
+type OptInt64 struct {
+	patchField
+	value int64
+}
+
+// Set sets the field to the value.
+func (o *OptInt64) Set(v int64) {
+	o.value, o.state = v, fieldSet
+}
+
+// Value returns the value and whether the field is set.
+func (o OptInt64) Value() (int64, bool) {
+	return o.value, o.IsSet()
+}
+
+func (o OptInt64) MarshalJSON() ([]byte, error) {
+	if !o.IsSet() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// OptBool is a bool patch field.
+type OptBool struct {
+	patchField
+	value bool
+}
+
+// Set sets the field to the value.
+func (o *OptBool) Set(v bool) {
+	o.value, o.state = v, fieldSet
+}
+
+// Value returns the value and whether the field is set.
+func (o OptBool) Value() (bool, bool) {
+	return o.value, o.IsSet()
+}
+
+func (o OptBool) MarshalJSON() ([]byte, error) {
+	if !o.IsSet() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// OptPhone is a Phone patch field.
+type OptPhone struct {
+	patchField
+	value Phone
+}
+
+// Set sets the field to the value.
+func (o *OptPhone) Set(v Phone) {
+	o.value, o.state = v, fieldSet
+}
+
+// Value returns the value and whether the field is set.
+func (o OptPhone) Value() (Phone, bool) {
+	return o.value, o.IsSet()
+}
+
+func (o OptPhone) MarshalJSON() ([]byte, error) {
+	if !o.IsSet() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// OptStrings is a string list patch field.
+type OptStrings struct {
+	patchField
+	value []string
+}
+
+// Set sets the field to the value.
+func (o *OptStrings) Set(v []string) {
+	o.value, o.state = append([]string{}, v...), fieldSet
+}
+
+// Value returns the value and whether the field is set.
+func (o OptStrings) Value() ([]string, bool) {
+	return o.value, o.IsSet()
+}
+
+func (o OptStrings) MarshalJSON() ([]byte, error) {
+	if !o.IsSet() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// optField is implemented by the patch fields.
+type optField interface {
+	json.Marshaler
+	changed() bool
+}
+
+// namedField is a patch field with its JSON name.
+type namedField struct {
+	name  string
+	field optField
+}
+
+// marshalPatch encodes the set fields with their
+// values and the cleared fields as null.
+func marshalPatch(fields []namedField) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for _, f := range fields {
+		if !f.field.changed() {
+			continue
+		}
+
+		v, err := f.field.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`"` + f.name + `":`)
+		buf.Write(v)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// changedFields returns the JSON names of the fields set or cleared.
+func changedFields(fields []namedField) []string {
+	var names []string
+	for _, f := range fields {
+		if f.field.changed() {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// EmployeePatch is a partial update of an employee. Only the fields
+// set or cleared are sent, so false and zero values can be sent too:
+//
+//	var p taxis99.EmployeePatch
+//	p.Enabled.Set(false)
+//	p.SupervisorID.Clear()
+type EmployeePatch struct {
+	Name         OptString
+	Email        OptString
+	Phone        OptPhone
+	NationalID   OptString
+	SupervisorID OptInt64
+	Enabled      OptBool
+	ExternalID   OptInt64
+	Categories   OptStrings
+}
+
+// fields returns the patch fields by their JSON name.
+func (p *EmployeePatch) fields() []namedField {
+	return []namedField{
+		{"name", p.Name},
+		{"email", p.Email},
+		{"phone", p.Phone},
+		{"nationalId", p.NationalID},
+		{"supervisorId", p.SupervisorID},
+		{"enabled", p.Enabled},
+		{"externalId", p.ExternalID},
+		{"categories", p.Categories},
+	}
+}
+
+// MarshalJSON encodes the set fields with their values
+// and the cleared fields as null.
+func (p EmployeePatch) MarshalJSON() ([]byte, error) {
+	return marshalPatch(p.fields())
+}
+
+// Fields returns the JSON names of the fields set or cleared.
+func (p EmployeePatch) Fields() []string {
+	return changedFields(p.fields())
+}
+
+// CostCenterPatch is a partial update of a cost center.
+// Only the fields set are sent, so Enabled can be set to false:
+//
+//	var p taxis99.CostCenterPatch
+//	p.Name.Set("Engineering")
+type CostCenterPatch struct {
+	Name    OptString
+	Enabled OptBool
+}
+
+func (p *CostCenterPatch) fields() []namedField {
+	return []namedField{
+		{"name", p.Name},
+		{"enabled", p.Enabled},
+	}
+}
+
+// MarshalJSON encodes the set fields with their values.
+func (p CostCenterPatch) MarshalJSON() ([]byte, error) {
+	return marshalPatch(p.fields())
+}
+
+// Fields returns the JSON names of the fields set or cleared.
+func (p CostCenterPatch) Fields() []string {
+	return changedFields(p.fields())
+}
+
+// NewEmployeePatch returns the patch of the employee fields in the
+// mask, named as in JSON, like supervisorId. Empty strings, zero IDs
+// and nil values clear the field; Enabled is always set.
+func NewEmployeePatch(emp Employee, mask ...string) (EmployeePatch, error) {
+	var p EmployeePatch
+
+	for _, name := range mask {
+		switch name {
+		case "name":
+			setString(&p.Name, emp.Name)
+		case "email":
+			setString(&p.Email, emp.Email)
+		case "phone":
+			if emp.Phone == nil {
+				p.Phone.Clear()
+			} else {
+				p.Phone.Set(*emp.Phone)
+			}
+		case "nationalId":
+			setString(&p.NationalID, emp.NationalID)
+		case "supervisorId":
+			setID(&p.SupervisorID, emp.SupervisorID)
+		case "enabled":
+			p.Enabled.Set(emp.Enabled)
+		case "externalId":
+			setID(&p.ExternalID, emp.ExternalID)
+		case "categories":
+			if emp.Categories == nil {
+				p.Categories.Clear()
+			} else {
+				p.Categories.Set(emp.Categories)
+			}
+		default:
+			return EmployeePatch{}, fmt.Errorf("taxis99: unknown employee field %q", name)
+		}
+	}
+
+	return p, nil
+}
+
+func setString(f *OptString, v string) {
+	if v == "" {
+		f.Clear()
+		return
+	}
+	f.Set(v)
+}
+
+func setID(f *OptInt64, id int64) {
+	if id == 0 {
+		f.Clear()
+		return
+	}
+	f.Set(id)
+}
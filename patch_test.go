@@ -0,0 +1,95 @@
+package taxis99
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEmployeePatchMarshal(t *testing.T) {
+	testCases := []struct {
+		name  string
+		patch func(p *EmployeePatch)
+		want  string
+	}{
+		{"Empty", func(p *EmployeePatch) {}, `{}`},
+		{"Set", func(p *EmployeePatch) {
+			p.Name.Set("José")
+			p.Phone.Set(Phone{Number: "11999999999", Country: "BR"})
+			p.Categories.Set([]string{"pop99"})
+		}, `{"name":"José","phone":{"number":"11999999999","country":"BR"},"categories":["pop99"]}`},
+		{"ZeroValues", func(p *EmployeePatch) {
+			p.Enabled.Set(false)
+			p.ExternalID.Set(0)
+			p.Categories.Set(nil)
+		}, `{"enabled":false,"externalId":0,"categories":[]}`},
+		{"Cleared", func(p *EmployeePatch) {
+			p.SupervisorID.Clear()
+			p.NationalID.Clear()
+		}, `{"nationalId":null,"supervisorId":null}`},
+		{"Unset", func(p *EmployeePatch) {
+			p.Email.Set("jose@example.com")
+			p.Email.Unset()
+			p.SupervisorID.Set(10)
+		}, `{"supervisorId":10}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var p EmployeePatch
+			tc.patch(&p)
+
+			got, err := json.Marshal(p)
+			if err != nil {
+				t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+			}
+
+			if string(got) != tc.want {
+				t.Errorf("Got patch %s; want %s.", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPatchFieldState(t *testing.T) {
+	var f OptInt64
+
+	if v, ok := f.Value(); ok || f.IsCleared() || v != 0 {
+		t.Errorf("Got unset field value %d, set %t and cleared %t; want neither.", v, ok, f.IsCleared())
+	}
+
+	f.Set(25)
+	if v, ok := f.Value(); !ok || v != 25 {
+		t.Errorf("Got field value %d and set %t; want 25 set.", v, ok)
+	}
+
+	f.Clear()
+	if _, ok := f.Value(); ok || !f.IsCleared() {
+		t.Errorf("Got field set %t and cleared %t; want cleared.", ok, f.IsCleared())
+	}
+}
+
+func TestNewEmployeePatch(t *testing.T) {
+	emp := Employee{Name: "José", SupervisorID: 10, Enabled: true}
+
+	p, err := NewEmployeePatch(emp, "enabled", "name", "supervisorId", "phone")
+	if err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	if got, want := p.Fields(), []string{"name", "phone", "supervisorId", "enabled"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got fields %v; want %v.", got, want)
+	}
+
+	if v, ok := p.SupervisorID.Value(); !ok || v != 10 {
+		t.Errorf("Got supervisorId %d set %t; want 10 set.", v, ok)
+	}
+
+	if !p.Phone.IsCleared() {
+		t.Error("Got phone not cleared; want the nil phone cleared.")
+	}
+
+	if _, err := NewEmployeePatch(emp, "id"); err == nil {
+		t.Error("Got error nil for the id field; want it not nil.")
+	}
+}
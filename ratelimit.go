@@ -0,0 +1,170 @@
+package taxis99
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// minRateFactor is the lowest fraction of the configured
+	// rate the limiter adapts to after 429 responses.
+	minRateFactor = 0.1
+	// recoverFactor is the fraction of the configured rate
+	// recovered after each successful response.
+	recoverFactor = 0.05
+)
+
+// RateLimiter is a token bucket limiter used by the Client to
+// stay under the API quota. Buckets can be set per endpoint
+// family, like employees or costcenters; other requests share
+// the default bucket. The rate halves when the server answers
+// 429 and slowly recovers on successful responses.
+type RateLimiter struct {
+	mu       sync.Mutex
+	def      *bucket
+	families map[string]*bucket
+}
+
+// NewRateLimiter returns a RateLimiter allowing rps requests
+// per second with bursts of up to burst requests.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		def:      newBucket(rps, burst),
+		families: map[string]*bucket{},
+	}
+}
+
+// SetFamily sets a dedicated bucket for the endpoint family,
+// which is the first segment of the request path.
+func (l *RateLimiter) SetFamily(family string, rps float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.families[family] = newBucket(rps, burst)
+}
+
+// Wait blocks until a request to path is allowed or the context is done.
+func (l *RateLimiter) Wait(ctx context.Context, path string) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	b := l.bucket(path)
+	d := b.reserve(time.Now())
+	l.mu.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, d); err != nil {
+		// Gives back the token not used.
+		l.mu.Lock()
+		b.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// throttled adapts the rate downward after a 429 response.
+func (l *RateLimiter) throttled(path string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(path)
+	b.refill(time.Now())
+	b.rate /= 2
+	if min := b.limit * minRateFactor; b.rate < min {
+		b.rate = min
+	}
+}
+
+// succeeded recovers the rate after a successful response.
+func (l *RateLimiter) succeeded(path string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(path)
+	if b.rate >= b.limit {
+		return
+	}
+	b.refill(time.Now())
+	b.rate += b.limit * recoverFactor
+	if b.rate > b.limit {
+		b.rate = b.limit
+	}
+}
+
+// bucket returns the path family bucket or the default one.
+// Must be called holding the lock.
+func (l *RateLimiter) bucket(path string) *bucket {
+	if b, ok := l.families[endpointFamily(path)]; ok {
+		return b
+	}
+	return l.def
+}
+
+// endpointFamily returns the first segment of the path.
+func endpointFamily(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.IndexAny(path, "/?"); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+type bucket struct {
+	// limit is the configured rate and rate the current one.
+	limit  float64
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rps float64, burst int) *bucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &bucket{
+		limit:  rps,
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// refill adds the tokens accumulated since the last call.
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+}
+
+// reserve takes a token and returns how long
+// to wait until it's available.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 || b.rate <= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
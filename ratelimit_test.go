@@ -0,0 +1,125 @@
+package taxis99
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	l := NewRateLimiter(100, 2)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(context.Background(), "employees"); err != nil {
+			t.Fatalf("Got error calling Wait: %s; want it to be nil.", err.Error())
+		}
+	}
+
+	// The burst is used at once and the next 2 requests wait 10ms each.
+	if got, want := time.Since(start), 15*time.Millisecond; got < want {
+		t.Errorf("Got waited %s; want at least %s.", got, want)
+	}
+}
+
+func TestRateLimiterWaitFamily(t *testing.T) {
+	l := NewRateLimiter(0.001, 1)
+	l.SetFamily("employees", 0.001, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Each family has its own burst.
+	for _, path := range []string{"employees?page=1", "costcenters/1"} {
+		if err := l.Wait(ctx, path); err != nil {
+			t.Fatalf("Got error calling Wait(%s): %s; want it to be nil.", path, err.Error())
+		}
+	}
+
+	if err := l.Wait(ctx, "employees/10/costcenter"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Got error '%v'; want context.DeadlineExceeded.", err)
+	}
+}
+
+func TestRateLimiterWaitContextCancelled(t *testing.T) {
+	l := NewRateLimiter(0.001, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	l.Wait(ctx, "")
+	if err := l.Wait(ctx, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("Got error '%v'; want context.Canceled.", err)
+	}
+
+	// The cancelled wait gives back its token.
+	if got := l.def.tokens; got < -0.01 || got > 0.01 {
+		t.Errorf("Got %f tokens; want 0.", got)
+	}
+}
+
+func TestRateLimiterAdapt(t *testing.T) {
+	l := NewRateLimiter(10, 1)
+
+	l.throttled("employees")
+	if got, want := l.def.rate, 5.0; got != want {
+		t.Errorf("Got rate %f after 429; want %f.", got, want)
+	}
+
+	for i := 0; i < 10; i++ {
+		l.throttled("employees")
+	}
+	if got, want := l.def.rate, 10*minRateFactor; got != want {
+		t.Errorf("Got rate %f after many 429; want %f.", got, want)
+	}
+
+	for i := 0; i < 100; i++ {
+		l.succeeded("employees")
+	}
+	if got, want := l.def.rate, 10.0; got != want {
+		t.Errorf("Got rate %f after recovering; want %f.", got, want)
+	}
+}
+
+func TestEndpointFamily(t *testing.T) {
+	testCases := []struct {
+		path string
+		want string
+	}{
+		{"employees", "employees"},
+		{"employees?page=2", "employees"},
+		{"employees/10/costcenter", "employees"},
+		{"/costcenters/1", "costcenters"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := endpointFamily(tc.path); got != tc.want {
+			t.Errorf("Got family '%s' for path '%s'; want '%s'.", got, tc.path, tc.want)
+		}
+	}
+}
+
+func TestClientRequestRateLimiter(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+
+	client, srv := newMockServer(nil, handler)
+	defer srv.Close()
+
+	client.RateLimiter = NewRateLimiter(1000, 10)
+	client.RateLimiter.SetFamily("employees", 1000, 10)
+
+	client.Request(context.Background(), http.MethodGet, "employees", nil, nil)
+
+	if got, want := client.RateLimiter.families["employees"].rate, 500.0; got != want {
+		t.Errorf("Got employees rate %f; want %f.", got, want)
+	}
+
+	if got, want := client.RateLimiter.def.rate, 1000.0; got != want {
+		t.Errorf("Got default rate %f; want %f.", got, want)
+	}
+}
@@ -0,0 +1,221 @@
+package taxis99
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// RecorderMode defines if the Recorder records or replays requests.
+type RecorderMode int
+
+const (
+	// ModeRecord sends the requests to the base RoundTripper
+	// and records them with their responses.
+	ModeRecord RecorderMode = iota
+	// ModeReplay answers the requests with the recorded
+	// responses, without sending them.
+	ModeReplay
+)
+
+// RecordedRequest is the request of an Interaction.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response of an Interaction.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction is a recorded request and response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// Recorder is the RoundTripper recording the requests and responses
+// to a cassette file, or replaying them from it. It complements the
+// Transport when used as its Base, in which case the API key is
+// redacted before recording.
+//
+// Requests are matched on method, path, query and body; each
+// recorded interaction is replayed only once, in order.
+type Recorder struct {
+	// Mode is either ModeRecord or ModeReplay.
+	Mode RecorderMode
+
+	// Path is the cassette file.
+	Path string
+
+	// Base is the base RoundTripper to make HTTP request
+	// in ModeRecord.
+	Base http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	replayed     []bool
+}
+
+// NewRecorder returns a Recorder for the cassette file. In ModeReplay
+// the cassette is loaded and must exist.
+func NewRecorder(path string, mode RecorderMode, base http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{
+		Mode: mode,
+		Path: path,
+		Base: base,
+	}
+
+	if mode == ModeReplay {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &r.interactions); err != nil {
+			return nil, fmt.Errorf("taxis99: invalid cassette %s: %w", path, err)
+		}
+		r.replayed = make([]bool, len(r.interactions))
+	}
+
+	return r, nil
+}
+
+// RoundTrip records or replays the request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recReq := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+		Header: redactHeader(req.Header),
+		Body:   string(body),
+	}
+
+	if r.Mode == ModeReplay {
+		return r.replay(req, recReq)
+	}
+
+	// The request body was consumed by readBody.
+	req = cloneReq(req)
+	if body != nil {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	res, err := r.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, &Interaction{
+		Request: recReq,
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     res.Header,
+			Body:       string(resBody),
+		},
+	})
+	r.mu.Unlock()
+
+	return res, nil
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.Path, b, 0644)
+}
+
+func (r *Recorder) replay(req *http.Request, recReq RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.interactions {
+		if r.replayed[i] || !in.Request.match(recReq) {
+			continue
+		}
+		r.replayed[i] = true
+
+		header := in.Response.Header
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewBufferString(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("taxis99: no recorded interaction for %s %s?%s in %s", recReq.Method, recReq.Path, recReq.Query, r.Path)
+}
+
+// base returns the base RoundTripper or the http default transport.
+func (r *Recorder) base() http.RoundTripper {
+	if r.Base != nil {
+		return r.Base
+	}
+
+	return http.DefaultTransport
+}
+
+// match reports whether the recorded request matches the other one.
+// JSON bodies are compared regardless of formatting.
+func (rr RecordedRequest) match(other RecordedRequest) bool {
+	return rr.Method == other.Method &&
+		rr.Path == other.Path &&
+		rr.Query == other.Query &&
+		compactJSON(rr.Body) == compactJSON(other.Body)
+}
+
+func compactJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
+// readBody reads and closes the request body.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	defer req.Body.Close()
+	return ioutil.ReadAll(req.Body)
+}
@@ -0,0 +1,165 @@
+package taxis99
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tempDir returns a temporary directory to be removed by the caller.
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "taxis99")
+	if err != nil {
+		t.Fatalf("Got error creating temp dir: %s; want it to be nil.", err.Error())
+	}
+	return dir
+}
+
+func TestRecorder(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		b, _ := ioutil.ReadAll(r.Body)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			w.Write(b)
+			return
+		}
+		w.Write([]byte(`[{"id":1,"name":"IT"}]`))
+	}))
+	defer srv.Close()
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	cassette := filepath.Join(dir, "cassette.json")
+
+	rec, err := NewRecorder(cassette, ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("Got error calling NewRecorder: %s; want it to be nil.", err.Error())
+	}
+
+	client, err := New(
+		WithHTTPClient(&http.Client{Transport: rec}),
+		WithAPIKey("secret-key"),
+		WithBaseURL(srv.URL),
+	)
+	if err != nil {
+		t.Fatalf("Got error calling New: %s; want it to be nil.", err.Error())
+	}
+
+	ctx := context.Background()
+	recFound, err := client.CostCenter.Find(ctx, Filter{"search": "IT", "limit": "10"})
+	if err != nil {
+		t.Fatalf("Got error recording CostCenter.Find: %s; want it to be nil.", err.Error())
+	}
+	recCreated, err := client.CostCenter.Create(ctx, CostCenter{Name: "Sales"})
+	if err != nil {
+		t.Fatalf("Got error recording CostCenter.Create: %s; want it to be nil.", err.Error())
+	}
+
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Got error calling Save: %s; want it to be nil.", err.Error())
+	}
+
+	b, _ := ioutil.ReadFile(cassette)
+	if strings.Contains(string(b), "secret-key") {
+		t.Errorf("Got API key in the cassette %s; want it redacted.", b)
+	}
+
+	var interactions []*Interaction
+	if err := json.Unmarshal(b, &interactions); err != nil || len(interactions) != 2 {
+		t.Fatalf("Got %d interactions (%v); want 2.", len(interactions), err)
+	}
+
+	// Replays without the server.
+	srv.Close()
+	calls = 0
+
+	rep, err := NewRecorder(cassette, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("Got error calling NewRecorder: %s; want it to be nil.", err.Error())
+	}
+
+	client.client.Transport.(*Transport).Base = rep
+
+	found, err := client.CostCenter.Find(ctx, Filter{"limit": "10", "search": "IT"})
+	if err != nil {
+		t.Fatalf("Got error replaying CostCenter.Find: %s; want it to be nil.", err.Error())
+	}
+	if len(found) != 1 || found[0].Name != recFound[0].Name {
+		t.Errorf("Got replayed cost centers %+v; want %+v.", found, recFound)
+	}
+
+	created, err := client.CostCenter.Create(ctx, CostCenter{Name: "Sales"})
+	if err != nil {
+		t.Fatalf("Got error replaying CostCenter.Create: %s; want it to be nil.", err.Error())
+	}
+	if created.Name != recCreated.Name {
+		t.Errorf("Got replayed cost center %+v; want %+v.", created, recCreated)
+	}
+
+	if calls != 0 {
+		t.Errorf("Got %d calls to the server while replaying; want 0.", calls)
+	}
+}
+
+func TestRecorderReplayNoMatch(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	cassette := filepath.Join(dir, "cassette.json")
+	ioutil.WriteFile(cassette, []byte(`[{"request":{"method":"POST","path":"/costcenters","body":"{\"name\":\"IT\"}"},"response":{"statusCode":201,"body":"{\"id\":1,\"name\":\"IT\"}"}}]`), 0644)
+
+	rep, err := NewRecorder(cassette, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("Got error calling NewRecorder: %s; want it to be nil.", err.Error())
+	}
+
+	client := NewClient(&http.Client{Transport: rep})
+	client.BaseURL, _ = client.BaseURL.Parse("/")
+
+	testCases := []struct {
+		name    string
+		method  string
+		path    string
+		body    interface{}
+		wantErr bool
+	}{
+		{"Method", http.MethodPut, "costcenters", CostCenter{Name: "IT"}, true},
+		{"Path", http.MethodPost, "employees", CostCenter{Name: "IT"}, true},
+		{"Query", http.MethodPost, "costcenters?page=2", CostCenter{Name: "IT"}, true},
+		{"Body", http.MethodPost, "costcenters", CostCenter{Name: "Sales"}, true},
+		{"Match", http.MethodPost, "costcenters", CostCenter{Name: "IT"}, false},
+		{"Replayed", http.MethodPost, "costcenters", CostCenter{Name: "IT"}, true},
+	}
+
+	for _, tc := range testCases {
+		var cc CostCenter
+		err := client.Request(context.Background(), tc.method, tc.path, tc.body, &cc)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%s: Got error '%v'; want error %t.", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestNewRecorderError(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	if _, err := NewRecorder(filepath.Join(dir, "missing.json"), ModeReplay, nil); err == nil {
+		t.Error("Got error nil for missing cassette; want it not to be nil.")
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	ioutil.WriteFile(invalid, []byte(`invalid`), 0644)
+	if _, err := NewRecorder(invalid, ModeReplay, nil); err == nil {
+		t.Error("Got error nil for invalid cassette; want it not to be nil.")
+	}
+}
@@ -0,0 +1,211 @@
+package taxis99
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 100 * time.Millisecond
+	defaultMaxDelay    = 5 * time.Second
+)
+
+// RetryPolicy defines how Client.Request retries failed requests.
+// The delay between attempts grows exponentially from BaseDelay
+// up to MaxDelay, unless the server answers with a Retry-After header.
+// A Retry-After longer than MaxDelay is not waited: the response is
+// returned instead, as retrying earlier would be throttled again.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts,
+	// including the first one.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts, including
+	// the one asked by a Retry-After header. Defaults to 5s.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction, between 0 and 1, of
+	// the delay that is randomized.
+	Jitter float64
+
+	// Statuses are the retryable HTTP status codes.
+	// Defaults to 429, 500, 502, 503 and 504.
+	Statuses []int
+
+	// RetryError reports whether a transport error is retryable.
+	// Defaults to timeouts, connection resets and unexpected EOFs.
+	RetryError func(error) bool
+
+	// RetryNonIdempotent allows retrying POST and PATCH requests,
+	// which may have been applied by the server before failing.
+	// POST requests with an idempotency key are always retried.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with 3 attempts,
+// 100ms base delay, 5s max delay and 20% of jitter.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: defaultMaxAttempts,
+		BaseDelay:   defaultBaseDelay,
+		MaxDelay:    defaultMaxDelay,
+		Jitter:      0.2,
+	}
+}
+
+var defaultRetryStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// retry reports whether the attempt should be retried
+// and how long to wait before the next one. Requests which
+// are not replayable are only retried if RetryNonIdempotent.
+func (p *RetryPolicy) retry(replayable bool, attempt int, res *http.Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if !p.RetryNonIdempotent && !replayable {
+		return 0, false
+	}
+
+	if err != nil {
+		if !p.retryError(err) {
+			return 0, false
+		}
+		return p.backoff(attempt), true
+	}
+
+	if !p.retryStatus(res.StatusCode) {
+		return 0, false
+	}
+
+	if d, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+		if d > p.maxDelay() {
+			return 0, false
+		}
+		return d, true
+	}
+
+	return p.backoff(attempt), true
+}
+
+func (p *RetryPolicy) retryStatus(status int) bool {
+	statuses := p.Statuses
+	if statuses == nil {
+		statuses = defaultRetryStatuses
+	}
+
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) retryError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if p.RetryError != nil {
+		return p.RetryError(err)
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoff returns the exponential delay for the attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	base, max := p.BaseDelay, p.maxDelay()
+	if base <= 0 {
+		base = defaultBaseDelay
+	}
+
+	d := float64(base) * math.Pow(2, float64(attempt-1))
+	if d > float64(max) {
+		d = float64(max)
+	}
+
+	if j := p.Jitter; j > 0 {
+		if j > 1 {
+			j = 1
+		}
+		d = d*(1-j) + rand.Float64()*d*j
+	}
+
+	return time.Duration(d)
+}
+
+func (p *RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay <= 0 {
+		return defaultMaxDelay
+	}
+	return p.MaxDelay
+}
+
+// idempotent reports whether the method can be safely replayed.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header, which
+// can be either in seconds or an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+// sleep waits for the duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
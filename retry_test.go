@@ -0,0 +1,222 @@
+package taxis99
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	}
+}
+
+func TestClientRequestRetry(t *testing.T) {
+	testCases := []struct {
+		name         string
+		method       string
+		policy       *RetryPolicy
+		statuses     []int
+		wantAttempts int
+		wantFail     bool
+	}{
+		{"NoPolicy", http.MethodGet, nil, []int{503, 200}, 1, true},
+		{"ServerError", http.MethodGet, testRetryPolicy(), []int{503, 500, 200}, 3, false},
+		{"TooManyRequests", http.MethodDelete, testRetryPolicy(), []int{429, 200}, 2, false},
+		{"MaxAttempts", http.MethodGet, testRetryPolicy(), []int{503, 503, 503, 200}, 3, true},
+		{"NotRetryable", http.MethodGet, testRetryPolicy(), []int{404, 200}, 1, true},
+		{"CustomStatuses", http.MethodGet, &RetryPolicy{MaxAttempts: 2, Statuses: []int{404}}, []int{404, 200}, 2, false},
+		{"Post", http.MethodPost, testRetryPolicy(), []int{503, 200}, 1, true},
+		{"Patch", http.MethodPatch, testRetryPolicy(), []int{503, 200}, 1, true},
+		{"PostAllowed", http.MethodPost, &RetryPolicy{MaxAttempts: 2, RetryNonIdempotent: true}, []int{503, 200}, 2, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int
+			var bodies []string
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				bodies = append(bodies, string(b))
+
+				status := tc.statuses[attempts]
+				attempts++
+
+				w.WriteHeader(status)
+				if status >= http.StatusBadRequest {
+					w.Write([]byte(`{"message":"error"}`))
+					return
+				}
+				w.Write([]byte(`{"name":"test"}`))
+			}
+
+			client, srv := newMockServer(nil, handler)
+			defer srv.Close()
+			client.Retry = tc.policy
+
+			var out struct {
+				Name string `json:"name"`
+			}
+			body := struct {
+				Name string `json:"name"`
+			}{"Test"}
+
+			err := client.Request(context.Background(), tc.method, "", body, &out)
+			if !tc.wantFail && (err != nil || out.Name != "test") {
+				t.Fatalf("Got error '%v' and response %+v; want success.", err, out)
+			}
+			if tc.wantFail && out.Name == "test" {
+				t.Fatalf("Got response %+v; want the request to fail.", out)
+			}
+
+			if attempts != tc.wantAttempts {
+				t.Errorf("Got %d attempts; want %d.", attempts, tc.wantAttempts)
+			}
+
+			for _, b := range bodies {
+				if b != bodies[0] {
+					t.Errorf("Got replayed body %s; want %s.", b, bodies[0])
+				}
+			}
+		})
+	}
+}
+
+func TestClientRequestRetryTransportError(t *testing.T) {
+	var attempts int
+	tripper := testRoundTripperFn(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, syscall.ECONNRESET
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       http.NoBody,
+			Header:     http.Header{},
+		}, nil
+	})
+
+	c := NewClient(&http.Client{Transport: tripper})
+	c.Retry = testRetryPolicy()
+
+	err := c.Request(context.Background(), http.MethodGet, "", nil, nil)
+	if err != nil {
+		t.Fatalf("Got error '%s'; want nil.", err.Error())
+	}
+
+	if attempts != 3 {
+		t.Errorf("Got %d attempts; want 3.", attempts)
+	}
+}
+
+func TestClientRequestRetryContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var attempts int
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	client, srv := newMockServer(nil, handler)
+	defer srv.Close()
+	client.Retry = &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second}
+
+	err := client.Request(ctx, http.MethodGet, "", nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Got error '%v'; want context.Canceled.", err)
+	}
+
+	if attempts != 1 {
+		t.Errorf("Got %d attempts; want 1.", attempts)
+	}
+}
+
+func TestRetryPolicyRetryAfter(t *testing.T) {
+	testCases := []struct {
+		header string
+		retry  bool
+		want   time.Duration
+	}{
+		{"2", true, 2 * time.Second},
+		{"0", true, 0},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), true, 0},
+		// Longer than MaxDelay.
+		{"3600", false, 0},
+		{time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), false, 0},
+	}
+
+	p := &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second}
+	for _, tc := range testCases {
+		res := &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{tc.header}},
+		}
+
+		got, ok := p.retry(true, 1, res, nil)
+		if ok != tc.retry {
+			t.Fatalf("Got retry %t for Retry-After '%s'; want %t.", ok, tc.header, tc.retry)
+		}
+
+		if got != tc.want {
+			t.Errorf("Got delay %s for Retry-After '%s'; want %s.", got, tc.header, tc.want)
+		}
+	}
+}
+
+func TestClientRequestRetryAfterMaxDelay(t *testing.T) {
+	var attempts int
+	client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer srv.Close()
+	client.Retry = testRetryPolicy()
+
+	err := client.Request(context.Background(), http.MethodGet, "", nil, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Got error '%v'; want the 429 response.", err)
+	}
+
+	if attempts != 1 {
+		t.Errorf("Got %d attempts; want 1.", attempts)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+	testCases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 10 * time.Millisecond},
+		{2, 20 * time.Millisecond},
+		{3, 40 * time.Millisecond},
+		{4, 50 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		if got := p.backoff(tc.attempt); got != tc.want {
+			t.Errorf("Got backoff %s for attempt %d; want %s.", got, tc.attempt, tc.want)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(2); got < 10*time.Millisecond || got > 20*time.Millisecond {
+			t.Fatalf("Got backoff with jitter %s; want between 10ms and 20ms.", got)
+		}
+	}
+}
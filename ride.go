@@ -0,0 +1,238 @@
+package taxis99
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	ridesEndpoint      endpoint = `rides`
+	rideEndpoint       endpoint = `rides/%d`
+	rideCancelEndpoint endpoint = `rides/%d/cancel`
+)
+
+// RideStatus is the state of a ride through its lifecycle.
+type RideStatus string
+
+const (
+	RideSearching  RideStatus = "SEARCHING"
+	RideAccepted   RideStatus = "ACCEPTED"
+	RideArriving   RideStatus = "ARRIVING"
+	RideInProgress RideStatus = "IN_PROGRESS"
+	RideFinished   RideStatus = "FINISHED"
+	RideCancelled  RideStatus = "CANCELLED"
+)
+
+// Done reports whether the ride reached a final status.
+func (s RideStatus) Done() bool {
+	return s == RideFinished || s == RideCancelled
+}
+
+// API error codes for ride requests.
+const (
+	codeNoDriversAvailable  = "error.noDriversAvailable"
+	codeRideAlreadyFinished = "error.rideAlreadyFinished"
+)
+
+var (
+	// ErrNoDriversAvailable is returned when no driver accepts the ride request.
+	ErrNoDriversAvailable = errors.New("taxis99: no drivers available")
+	// ErrRideAlreadyFinished is returned when cancelling a ride that is already over.
+	ErrRideAlreadyFinished = errors.New("taxis99: ride already finished")
+)
+
+// Hashset for allowed query params.
+var rideFields = map[string]struct{}{
+	"startDate":    struct{}{},
+	"endDate":      struct{}{},
+	"employeeId":   struct{}{},
+	"costCenterId": struct{}{},
+	"status":       struct{}{},
+	"limit":        struct{}{},
+	"page":         struct{}{},
+}
+
+// Location is an address with its coordinates.
+type Location struct {
+	Address   string  `json:"address,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+}
+
+// Fare is the price breakdown of a ride.
+type Fare struct {
+	Total    float64 `json:"total,omitempty"`
+	Base     float64 `json:"base,omitempty"`
+	Distance float64 `json:"distance,omitempty"`
+	Time     float64 `json:"time,omitempty"`
+	Tolls    float64 `json:"tolls,omitempty"`
+	Parking  float64 `json:"parking,omitempty"`
+	Discount float64 `json:"discount,omitempty"`
+	Currency string  `json:"currency,omitempty"`
+}
+
+type Driver struct {
+	Name     string `json:"name,omitempty"`
+	Phone    *Phone `json:"phone,omitempty"`
+	CarModel string `json:"carModel,omitempty"`
+	CarPlate string `json:"carPlate,omitempty"`
+}
+
+type Ride struct {
+	ID            int64       `json:"id,omitempty"`
+	Status        RideStatus  `json:"status,omitempty"`
+	Category      string      `json:"category,omitempty"`
+	Pickup        *Location   `json:"pickup,omitempty"`
+	Dropoff       *Location   `json:"dropoff,omitempty"`
+	RequestedAt   *time.Time  `json:"requestedAt,omitempty"`
+	PickedUpAt    *time.Time  `json:"pickedUpAt,omitempty"`
+	FinishedAt    *time.Time  `json:"finishedAt,omitempty"`
+	Distance      float64     `json:"distance,omitempty"`
+	Fare          *Fare       `json:"fare,omitempty"`
+	Driver        *Driver     `json:"driver,omitempty"`
+	Employee      *Employee   `json:"employee,omitempty"`
+	CostCenter    *CostCenter `json:"costCenter,omitempty"`
+	Project       string      `json:"project,omitempty"`
+	Justification string      `json:"justification,omitempty"`
+}
+
+// RideRequest is the data needed to request a ride for an employee.
+type RideRequest struct {
+	EmployeeID    int64     `json:"employeeId,omitempty"`
+	Origin        *Location `json:"origin,omitempty"`
+	Destination   *Location `json:"destination,omitempty"`
+	Category      string    `json:"category,omitempty"`
+	CostCenterID  int64     `json:"costCenterId,omitempty"`
+	Project       string    `json:"project,omitempty"`
+	Justification string    `json:"justification,omitempty"`
+}
+
+type RideService service
+
+// Find returns the company rides. Rides can be filtered by
+// startDate, endDate, employeeId, costCenterId and status.
+func (r *RideService) Find(ctx context.Context, f Filter) ([]*Ride, error) {
+	var rides []*Ride
+
+	v := f.values(rideFields)
+
+	err := r.client.Request(withOperation(ctx, "Ride.Find"), http.MethodGet, string(ridesEndpoint.Query(v)), nil, &rides)
+	if err != nil {
+		return nil, err
+	}
+
+	return rides, nil
+}
+
+func (r *RideService) Get(ctx context.Context, id int64) (*Ride, error) {
+	ride := new(Ride)
+
+	endpoint := fmt.Sprintf(string(rideEndpoint), id)
+
+	err := r.client.Request(withOperation(ctx, "Ride.Get"), http.MethodGet, endpoint, nil, ride)
+	if err != nil {
+		return nil, err
+	}
+
+	return ride, nil
+}
+
+// Create requests a ride on behalf of the employee. The returned ride
+// starts as RideSearching; use Status to follow it.
+func (r *RideService) Create(ctx context.Context, emp Employee, req RideRequest) (*Ride, error) {
+	ride := new(Ride)
+
+	req.EmployeeID = emp.ID
+
+	err := r.client.Request(withOperation(ctx, "Ride.Create"), http.MethodPost, string(ridesEndpoint), req, ride)
+	if err != nil {
+		return nil, err
+	}
+
+	return ride, nil
+}
+
+// Status returns the current status of the ride.
+func (r *RideService) Status(ctx context.Context, id int64) (RideStatus, error) {
+	ride, err := r.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	return ride.Status, nil
+}
+
+// Cancel cancels the ride with the given reason.
+func (r *RideService) Cancel(ctx context.Context, id int64, reason string) error {
+	type cancelRide struct {
+		Reason string `json:"reason,omitempty"`
+	}
+
+	endpoint := fmt.Sprintf(string(rideCancelEndpoint), id)
+
+	return r.client.Request(withOperation(ctx, "Ride.Cancel"), http.MethodPost, endpoint, cancelRide{reason}, nil)
+}
+
+// RideIterator iterates over all the rides returned by Find,
+// walking through every page.
+type RideIterator struct {
+	p   *pager
+	buf []*Ride
+	cur *Ride
+}
+
+// Next advances to the next ride. It returns false when there are
+// no more rides, the context is done or an error happened.
+func (it *RideIterator) Next() bool {
+	if it.p.checkContext() {
+		it.cur = nil
+		return false
+	}
+
+	for len(it.buf) == 0 {
+		if !it.p.next() {
+			it.cur = nil
+			return false
+		}
+	}
+
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Ride returns the current ride.
+func (it *RideIterator) Ride() *Ride {
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *RideIterator) Err() error {
+	return it.p.err
+}
+
+// All returns an iterator over all the rides matching the filter.
+// The iteration starts at the filter page or at the first one.
+func (r *RideService) All(ctx context.Context, f Filter) *RideIterator {
+	it := new(RideIterator)
+	it.p = newPager(ctx, f, func(ctx context.Context, f Filter) (int, error) {
+		var err error
+		it.buf, err = r.Find(ctx, f)
+		return len(it.buf), err
+	})
+	return it
+}
+
+// Each calls fn for all the rides matching the filter.
+// It stops at the first error returned by fn.
+func (r *RideService) Each(ctx context.Context, f Filter, fn func(*Ride) error) error {
+	it := r.All(ctx, f)
+	for it.Next() {
+		if err := fn(it.Ride()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
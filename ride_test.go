@@ -0,0 +1,232 @@
+package taxis99
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestRideFind(t *testing.T) {
+	testPath(t, string(ridesEndpoint), func(c *Client) error {
+		_, err := c.Ride.Find(context.Background(), nil)
+		return err
+	})
+
+	testMethod(t, http.MethodGet, func(c *Client) error {
+		_, err := c.Ride.Find(context.Background(), nil)
+		return err
+	})
+
+	testQuery(t, []Filter{
+		{"startDate": "2019-10-01", "endDate": "2019-10-31"},
+		{"employeeId": "125", "costCenterId": "77045", "status": "FINISHED"},
+		{"limit": "100", "page": "2", "invalid": "param"},
+	}, rideFields, func(c *Client, f Filter) error {
+		_, err := c.Ride.Find(context.Background(), f)
+		return err
+	})
+
+	testResponseBody(t, [][]byte{
+		[]byte(`[{"id":3001,"status":"FINISHED","category":"pop99","pickup":{"address":"Av. Paulista, 1000","latitude":-23.5648,"longitude":-46.6519},"dropoff":{"address":"Rua Augusta, 500","latitude":-23.5534,"longitude":-46.6559},"requestedAt":"2019-10-10T09:00:00Z","pickedUpAt":"2019-10-10T09:05:00Z","finishedAt":"2019-10-10T09:25:00Z","distance":3200,"fare":{"total":25.5,"base":5,"distance":12.3,"time":8.2,"currency":"BRL"},"driver":{"name":"Carlos","carModel":"Onix","carPlate":"ABC1234"},"employee":{"id":125,"name":"José Santos"},"costCenter":{"id":77045,"name":"IT"},"project":"Onboarding","justification":"Client meeting"}]`),
+	}, func(c *Client) (interface{}, error) {
+		return c.Ride.Find(context.Background(), nil)
+	})
+}
+
+func TestRideFindError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.Ride.Find(context.Background(), nil)
+		return err
+	})
+}
+
+func TestRideGet(t *testing.T) {
+	testCases := []struct {
+		id   int64
+		want string
+	}{
+		{25, fmt.Sprintf(string(rideEndpoint), 25)},
+		{28, fmt.Sprintf(string(rideEndpoint), 28)},
+	}
+
+	for _, tc := range testCases {
+		testPath(t, tc.want, func(c *Client) error {
+			_, err := c.Ride.Get(context.Background(), tc.id)
+			return err
+		})
+	}
+
+	testMethod(t, http.MethodGet, func(c *Client) error {
+		_, err := c.Ride.Get(context.Background(), 20)
+		return err
+	})
+
+	testResponseBody(t, [][]byte{
+		[]byte(`{"id":3001,"status":"FINISHED","category":"pop99","pickup":{"address":"Av. Paulista, 1000","latitude":-23.5648,"longitude":-46.6519},"fare":{"total":25.5,"currency":"BRL"}}`),
+	}, func(c *Client) (interface{}, error) {
+		return c.Ride.Get(context.Background(), 3001)
+	})
+}
+
+func TestRideGetError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.Ride.Get(context.Background(), 0)
+		return err
+	})
+}
+
+func TestRideCreate(t *testing.T) {
+	testPath(t, string(ridesEndpoint), func(c *Client) error {
+		_, err := c.Ride.Create(context.Background(), Employee{}, RideRequest{})
+		return err
+	})
+
+	testMethod(t, http.MethodPost, func(c *Client) error {
+		_, err := c.Ride.Create(context.Background(), Employee{}, RideRequest{})
+		return err
+	})
+
+	testResponseBody(t, [][]byte{
+		[]byte(`{"id":3002,"status":"SEARCHING","category":"pop99"}`),
+	}, func(c *Client) (interface{}, error) {
+		return c.Ride.Create(context.Background(), Employee{}, RideRequest{})
+	})
+
+	testRequestBody(t, []func(*Client) ([]byte, error){
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"employeeId":125,"origin":{"address":"Av. Paulista, 1000","latitude":-23.5648,"longitude":-46.6519},"destination":{"address":"Rua Augusta, 500"},"category":"pop99","costCenterId":77045,"justification":"Client meeting"}`)
+			_, err = c.Ride.Create(context.Background(), Employee{ID: 125}, RideRequest{
+				Origin: &Location{
+					Address:   "Av. Paulista, 1000",
+					Latitude:  -23.5648,
+					Longitude: -46.6519,
+				},
+				Destination:   &Location{Address: "Rua Augusta, 500"},
+				Category:      "pop99",
+				CostCenterID:  77045,
+				Justification: "Client meeting",
+			})
+			return
+		},
+	})
+}
+
+func TestRideCreateError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.Ride.Create(context.Background(), Employee{}, RideRequest{})
+		return err
+	})
+
+	t.Run("NoDriversAvailable", func(t *testing.T) {
+		client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"code":"error.noDriversAvailable","message":"No drivers available"}`))
+		})
+		defer srv.Close()
+
+		_, err := client.Ride.Create(context.Background(), Employee{ID: 1}, RideRequest{})
+		if !errors.Is(err, ErrNoDriversAvailable) {
+			t.Errorf("Got error '%v'; want ErrNoDriversAvailable.", err)
+		}
+	})
+}
+
+func TestRideStatus(t *testing.T) {
+	testPath(t, fmt.Sprintf(string(rideEndpoint), 25), func(c *Client) error {
+		_, err := c.Ride.Status(context.Background(), 25)
+		return err
+	})
+
+	testCases := []struct {
+		response []byte
+		want     RideStatus
+	}{
+		{[]byte(`{"id":1,"status":"SEARCHING"}`), RideSearching},
+		{[]byte(`{"id":1,"status":"ARRIVING"}`), RideArriving},
+		{[]byte(`{"id":1,"status":"CANCELLED"}`), RideCancelled},
+	}
+
+	for _, tc := range testCases {
+		request := func(ctx context.Context, method, path string, body, output interface{}) error {
+			return json.Unmarshal(tc.response, output)
+		}
+
+		c := newMockRequesterClient(mockRequester(request))
+
+		got, err := c.Ride.Status(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+		}
+
+		if got != tc.want {
+			t.Errorf("Got status %s; want %s.", got, tc.want)
+		}
+	}
+}
+
+func TestRideStatusError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		_, err := c.Ride.Status(context.Background(), 0)
+		return err
+	})
+}
+
+func TestRideStatusDone(t *testing.T) {
+	testCases := []struct {
+		status RideStatus
+		want   bool
+	}{
+		{RideSearching, false},
+		{RideAccepted, false},
+		{RideArriving, false},
+		{RideInProgress, false},
+		{RideFinished, true},
+		{RideCancelled, true},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.status.Done(); got != tc.want {
+			t.Errorf("Got %s.Done() %t; want %t.", tc.status, got, tc.want)
+		}
+	}
+}
+
+func TestRideCancel(t *testing.T) {
+	testPath(t, fmt.Sprintf(string(rideCancelEndpoint), 25), func(c *Client) error {
+		return c.Ride.Cancel(context.Background(), 25, "")
+	})
+
+	testMethod(t, http.MethodPost, func(c *Client) error {
+		return c.Ride.Cancel(context.Background(), 25, "")
+	})
+
+	testRequestBody(t, []func(*Client) ([]byte, error){
+		func(c *Client) (want []byte, err error) {
+			want = []byte(`{"reason":"Meeting cancelled"}`)
+			err = c.Ride.Cancel(context.Background(), 25, "Meeting cancelled")
+			return
+		},
+	})
+}
+
+func TestRideCancelError(t *testing.T) {
+	testError(t, func(c *Client) error {
+		return c.Ride.Cancel(context.Background(), 0, "")
+	})
+
+	t.Run("AlreadyFinished", func(t *testing.T) {
+		client, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"code":"error.rideAlreadyFinished","message":"Ride already finished"}`))
+		})
+		defer srv.Close()
+
+		err := client.Ride.Cancel(context.Background(), 25, "Meeting cancelled")
+		if !errors.Is(err, ErrRideAlreadyFinished) {
+			t.Errorf("Got error '%v'; want ErrRideAlreadyFinished.", err)
+		}
+	})
+}
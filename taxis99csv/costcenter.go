@@ -0,0 +1,139 @@
+package taxis99csv
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+// CostCenterColumns are the CSV headers of the cost center fields.
+// Empty headers are not read nor written. Name is required when reading.
+type CostCenterColumns struct {
+	ID      string
+	Name    string
+	Enabled string
+}
+
+// DefaultCostCenterColumns are the default cost center headers.
+var DefaultCostCenterColumns = CostCenterColumns{
+	ID:      "id",
+	Name:    "name",
+	Enabled: "enabled",
+}
+
+func (c CostCenterColumns) list() []string {
+	return []string{c.ID, c.Name, c.Enabled}
+}
+
+// CostCenterRow is a cost center read from a CSV row.
+type CostCenterRow struct {
+	// Line is the line of the row in the file. See RowError.
+	Line       int
+	CostCenter taxis99.CostCenter
+}
+
+// CostCenterReader reads cost centers from CSV.
+type CostCenterReader struct {
+	cols CostCenterColumns
+	r    *reader
+}
+
+// NewCostCenterReader returns a reader of the CSV with a header row.
+func NewCostCenterReader(r io.Reader, cols CostCenterColumns) *CostCenterReader {
+	return &CostCenterReader{cols: cols, r: newReader(r)}
+}
+
+// Read returns the next cost center, or io.EOF at the end. Invalid
+// rows return a *RowError, after which reading can go on. An invalid
+// header, at line 1, is returned on every call.
+func (r *CostCenterReader) Read() (*CostCenterRow, error) {
+	if err := r.r.readHeader(r.cols.Name); err != nil {
+		return nil, err
+	}
+
+	rec, err := r.r.next()
+	if err != nil {
+		return nil, err
+	}
+
+	row := &CostCenterRow{
+		Line: rec.line,
+		CostCenter: taxis99.CostCenter{
+			ID:      rec.int64(r.cols.ID),
+			Name:    rec.required(r.cols.Name),
+			Enabled: rec.bool(r.cols.Enabled),
+		},
+	}
+
+	if rec.err != nil {
+		return nil, rec.err
+	}
+	return row, nil
+}
+
+// ReadAll reads all the valid cost centers. Invalid rows
+// are skipped and returned as Errors.
+func (r *CostCenterReader) ReadAll() ([]*CostCenterRow, error) {
+	var (
+		rows []*CostCenterRow
+		errs Errors
+	)
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if rowErr, ok := r.r.rowError(err); ok {
+			errs = append(errs, rowErr)
+			continue
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, row)
+	}
+
+	if len(errs) > 0 {
+		return rows, errs
+	}
+	return rows, nil
+}
+
+// CostCenterWriter writes cost centers as CSV.
+type CostCenterWriter struct {
+	cols CostCenterColumns
+	w    *writer
+}
+
+// NewCostCenterWriter returns a writer of the configured columns.
+// The header is written with the first cost center, or by Flush.
+func NewCostCenterWriter(w io.Writer, cols CostCenterColumns) *CostCenterWriter {
+	return &CostCenterWriter{cols: cols, w: newWriter(w, cols.list())}
+}
+
+// Write writes the cost center.
+func (w *CostCenterWriter) Write(cc *taxis99.CostCenter) error {
+	return w.w.write(map[string]string{
+		w.cols.ID:      formatInt(cc.ID),
+		w.cols.Name:    cc.Name,
+		w.cols.Enabled: strconv.FormatBool(cc.Enabled),
+	})
+}
+
+// Flush writes any buffered data.
+func (w *CostCenterWriter) Flush() error {
+	return w.w.flush()
+}
+
+// ExportCostCenters writes all the cost centers matching the filter.
+func ExportCostCenters(ctx context.Context, api taxis99.CostCenterAPI, w io.Writer, cols CostCenterColumns, f taxis99.Filter) error {
+	cw := NewCostCenterWriter(w, cols)
+
+	if err := api.Each(ctx, f, cw.Write); err != nil {
+		return err
+	}
+
+	return cw.Flush()
+}
@@ -0,0 +1,111 @@
+package taxis99csv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mobilitee-smartmob/taxis99"
+	"github.com/mobilitee-smartmob/taxis99/taxis99test"
+)
+
+func TestCostCenterReaderReadAll(t *testing.T) {
+	in := "ID,Name,Enabled\n" +
+		"1,Sales,true\n" +
+		",,true\n" +
+		"x,IT,false\n" +
+		",Marketing,\n"
+
+	rows, err := NewCostCenterReader(strings.NewReader(in), DefaultCostCenterColumns).ReadAll()
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Got error %v; want Errors.", err)
+	}
+
+	wantErrs := []struct {
+		line   int
+		column string
+	}{
+		{3, "name"},
+		{4, "id"},
+	}
+	if len(errs) != len(wantErrs) {
+		t.Fatalf("Got %d errors: %v; want %d.", len(errs), errs, len(wantErrs))
+	}
+	for i, w := range wantErrs {
+		if errs[i].Line != w.line || errs[i].Column != w.column {
+			t.Errorf("Got error %v; want line %d column '%s'.", errs[i], w.line, w.column)
+		}
+	}
+	if !errors.Is(errs[0], ErrRequired) {
+		t.Errorf("Got error %v; want ErrRequired.", errs[0])
+	}
+
+	want := []*CostCenterRow{
+		{Line: 2, CostCenter: taxis99.CostCenter{ID: 1, Name: "Sales", Enabled: true}},
+		{Line: 5, CostCenter: taxis99.CostCenter{Name: "Marketing"}},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Got rows %+v; want %+v.", rows, want)
+	}
+}
+
+func TestCostCenterReaderMissingColumn(t *testing.T) {
+	_, err := NewCostCenterReader(strings.NewReader("id\n1\n"), DefaultCostCenterColumns).Read()
+
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) || !errors.Is(err, ErrMissingColumn) {
+		t.Fatalf("Got error %v; want ErrMissingColumn.", err)
+	}
+	if rowErr.Line != 1 || rowErr.Column != "name" {
+		t.Errorf("Got error at line %d column '%s'; want line 1 column 'name'.", rowErr.Line, rowErr.Column)
+	}
+}
+
+func TestExportCostCenters(t *testing.T) {
+	srv := taxis99test.NewServer("key")
+	defer srv.Close()
+
+	srv.AddCostCenter(taxis99.CostCenter{Name: "Sales", Enabled: true})
+	srv.AddCostCenter(taxis99.CostCenter{Name: "IT"})
+
+	var buf bytes.Buffer
+	if err := ExportCostCenters(context.Background(), srv.Client().CostCenter, &buf, DefaultCostCenterColumns, nil); err != nil {
+		t.Fatalf("Got error calling ExportCostCenters: %s; want it to be nil.", err.Error())
+	}
+
+	want := "id,name,enabled\n1,Sales,true\n2,IT,false\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Got CSV:\n%s\nwant:\n%s", got, want)
+	}
+
+	// The exported CSV is read back as the same cost centers.
+	rows, err := NewCostCenterReader(&buf, DefaultCostCenterColumns).ReadAll()
+	if err != nil {
+		t.Fatalf("Got error calling ReadAll: %s; want it to be nil.", err.Error())
+	}
+	for i, cc := range srv.CostCenters() {
+		cc.Company = nil
+		if !reflect.DeepEqual(&rows[i].CostCenter, cc) {
+			t.Errorf("Got cost center %+v; want %+v.", rows[i].CostCenter, cc)
+		}
+	}
+}
+
+func TestExportCostCentersEmpty(t *testing.T) {
+	srv := taxis99test.NewServer("key")
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	if err := ExportCostCenters(context.Background(), srv.Client().CostCenter, &buf, DefaultCostCenterColumns, nil); err != nil {
+		t.Fatalf("Got error calling ExportCostCenters: %s; want it to be nil.", err.Error())
+	}
+
+	if got, want := buf.String(), "id,name,enabled\n"; got != want {
+		t.Errorf("Got CSV %q; want only the header %q.", got, want)
+	}
+}
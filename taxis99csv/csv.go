@@ -0,0 +1,359 @@
+// Package taxis99csv maps CSV spreadsheets to taxis99 employees and
+// cost centers, and exports them back to CSV.
+//
+//	r := taxis99csv.NewEmployeeReader(f, taxis99csv.DefaultEmployeeColumns)
+//	for {
+//		row, err := r.Read()
+//		if err == io.EOF {
+//			break
+//		}
+//		var rowErr *taxis99csv.RowError
+//		if errors.As(err, &rowErr) && rowErr.Line > 1 {
+//			log.Printf("skipping %v", rowErr)
+//			continue
+//		}
+//		...
+//	}
+package taxis99csv
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultListSep separates the values of list columns, like categories.
+const DefaultListSep = ";"
+
+var (
+	// ErrRequired is the error of a required column left empty.
+	ErrRequired = errors.New("required")
+	// ErrMissingColumn is the error of a header without a required column.
+	ErrMissingColumn = errors.New("missing column")
+)
+
+// RowError is the error of an invalid row. Line is its line in the
+// file, the header being line 1. Rows with line breaks in quoted
+// cells are at the line they start.
+type RowError struct {
+	Line   int
+	Column string
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("taxis99csv: line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("taxis99csv: line %d: column %q: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Errors lists the row errors found by ReadAll.
+type Errors []*RowError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// reader reads the records by column header.
+type reader struct {
+	src   *bufio.Reader
+	comma rune
+	index map[string]int
+	// line is the number of lines read from src.
+	line   int
+	header bool
+	// headerErr is returned by every read after an invalid header.
+	headerErr error
+}
+
+func newReader(r io.Reader) *reader {
+	return &reader{src: bufio.NewReader(r), comma: ','}
+}
+
+// read returns the next record and the line it starts at, skipping
+// empty lines. The records are split from src before parsing them, as
+// encoding/csv doesn't tell the line of a record before Go 1.17.
+func (r *reader) read() ([]string, int, error) {
+	for {
+		start := r.line + 1
+		raw, err := r.readRaw()
+		if err != nil && (err != io.EOF || raw == "") {
+			return nil, start, err
+		}
+
+		cr := csv.NewReader(strings.NewReader(raw))
+		cr.Comma = r.comma
+		cr.FieldsPerRecord = -1
+		cr.TrimLeadingSpace = true
+
+		rec, err := cr.Read()
+		if err == io.EOF {
+			continue
+		}
+		if pe, ok := err.(*csv.ParseError); ok {
+			return nil, start, &RowError{Line: start + pe.Line - 1, Err: pe.Err}
+		}
+		if err != nil {
+			return nil, start, err
+		}
+		return rec, start, nil
+	}
+}
+
+// readRaw reads the lines of the next record, which
+// go on while a quoted field is not closed.
+func (r *reader) readRaw() (string, error) {
+	var (
+		b          strings.Builder
+		quoted     bool
+		fieldStart = true
+	)
+	for {
+		line, err := r.src.ReadString('\n')
+		if line != "" {
+			r.line++
+			b.WriteString(line)
+		}
+
+		runes := []rune(line)
+		for i := 0; i < len(runes); i++ {
+			c := runes[i]
+			switch {
+			case quoted && c == '"':
+				// A doubled quote is an escaped one.
+				if i+1 < len(runes) && runes[i+1] == '"' {
+					i++
+				} else {
+					quoted = false
+				}
+			case quoted:
+			case c == r.comma:
+				fieldStart = true
+			case fieldStart && c == '"':
+				quoted, fieldStart = true, false
+			case fieldStart && unicode.IsSpace(c):
+			default:
+				fieldStart = false
+			}
+		}
+
+		if err != nil || !quoted {
+			return b.String(), err
+		}
+	}
+}
+
+// readHeader reads the header once, checking it has the required columns.
+func (r *reader) readHeader(required ...string) error {
+	if r.header {
+		return r.headerErr
+	}
+
+	r.header = true
+
+	rec, line, err := r.read()
+	if err != nil {
+		r.headerErr = err
+		return err
+	}
+
+	r.index = make(map[string]int, len(rec))
+	for i, h := range rec {
+		r.index[normalize(h)] = i
+	}
+
+	for _, col := range required {
+		if _, ok := r.index[normalize(col)]; col != "" && !ok {
+			r.headerErr = &RowError{Line: line, Column: col, Err: ErrMissingColumn}
+			return r.headerErr
+		}
+	}
+	return nil
+}
+
+// rowError reports whether the error is of a single row, after
+// which reading can go on. Header errors are returned on every read.
+func (r *reader) rowError(err error) (*RowError, bool) {
+	rowErr, ok := err.(*RowError)
+	if !ok || err == r.headerErr {
+		return nil, false
+	}
+	return rowErr, true
+}
+
+// next returns the next record, skipping the ones without values.
+func (r *reader) next() (*record, error) {
+	for {
+		rec, line, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+
+		if !blank(rec) {
+			return &record{r: r, fields: rec, line: line}, nil
+		}
+	}
+}
+
+// record is a CSV record whose fields are parsed
+// by column, keeping the first error.
+type record struct {
+	r      *reader
+	fields []string
+	line   int
+	err    *RowError
+}
+
+// get returns the trimmed value of the column, which is
+// empty if the column is not configured or not in the header.
+func (rec *record) get(col string) string {
+	if col == "" {
+		return ""
+	}
+	i, ok := rec.r.index[normalize(col)]
+	if !ok || i >= len(rec.fields) {
+		return ""
+	}
+	return strings.TrimSpace(rec.fields[i])
+}
+
+func (rec *record) fail(col string, err error) {
+	if rec.err == nil {
+		rec.err = &RowError{Line: rec.line, Column: col, Err: err}
+	}
+}
+
+func (rec *record) required(col string) string {
+	v := rec.get(col)
+	if v == "" && col != "" {
+		rec.fail(col, ErrRequired)
+	}
+	return v
+}
+
+func (rec *record) int64(col string) int64 {
+	v := rec.get(col)
+	if v == "" {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		rec.fail(col, fmt.Errorf("invalid number %q", v))
+	}
+	return n
+}
+
+func (rec *record) bool(col string) bool {
+	v := rec.get(col)
+	if v == "" {
+		return false
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		rec.fail(col, fmt.Errorf("invalid boolean %q", v))
+	}
+	return b
+}
+
+func (rec *record) list(col, sep string) []string {
+	v := rec.get(col)
+	if v == "" {
+		return nil
+	}
+
+	var list []string
+	for _, s := range strings.Split(v, sep) {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// writer writes the records of the configured columns.
+type writer struct {
+	csv    *csv.Writer
+	cols   []string
+	header bool
+}
+
+func newWriter(w io.Writer, cols []string) *writer {
+	// Only the configured columns are written.
+	var used []string
+	for _, c := range cols {
+		if c != "" {
+			used = append(used, c)
+		}
+	}
+	return &writer{csv: csv.NewWriter(w), cols: used}
+}
+
+// writeHeader writes the header once.
+func (w *writer) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	return w.csv.Write(w.cols)
+}
+
+// write writes the values by column, after the header.
+func (w *writer) write(values map[string]string) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	rec := make([]string, len(w.cols))
+	for i, c := range w.cols {
+		rec[i] = values[c]
+	}
+	return w.csv.Write(rec)
+}
+
+// flush writes the header too if no record was written,
+// so an empty export still has its columns.
+func (w *writer) flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func formatInt(n int64) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// normalize makes the header matching case insensitive,
+// ignoring the byte order mark of spreadsheet exports.
+func normalize(h string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+}
+
+func blank(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,225 @@
+package taxis99csv
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+// EmployeeColumns are the CSV headers of the employee fields.
+// Empty headers are not read nor written. Name and Email are
+// required when reading.
+type EmployeeColumns struct {
+	ID           string
+	Name         string
+	Email        string
+	PhoneNumber  string
+	PhoneCountry string
+	NationalID   string
+	SupervisorID string
+	Enabled      string
+	ExternalID   string
+	Categories   string
+	// CostCenters is the list of the employee cost center names.
+	CostCenters string
+}
+
+// DefaultEmployeeColumns are the default employee headers.
+var DefaultEmployeeColumns = EmployeeColumns{
+	ID:           "id",
+	Name:         "name",
+	Email:        "email",
+	PhoneNumber:  "phone",
+	PhoneCountry: "phone_country",
+	NationalID:   "national_id",
+	SupervisorID: "supervisor_id",
+	Enabled:      "enabled",
+	ExternalID:   "external_id",
+	Categories:   "categories",
+	CostCenters:  "cost_centers",
+}
+
+func (c EmployeeColumns) list() []string {
+	return []string{
+		c.ID, c.Name, c.Email, c.PhoneNumber, c.PhoneCountry, c.NationalID,
+		c.SupervisorID, c.Enabled, c.ExternalID, c.Categories, c.CostCenters,
+	}
+}
+
+// EmployeeRow is an employee read from a CSV row.
+type EmployeeRow struct {
+	// Line is the line of the row in the file. See RowError.
+	Line     int
+	Employee taxis99.Employee
+	// CostCenters are the names of the employee cost centers,
+	// which are assigned with EmployeeService.UpdateCostCenters.
+	CostCenters []string
+}
+
+// EmployeeReader reads employees from CSV.
+type EmployeeReader struct {
+	// ListSep separates the categories and cost centers.
+	// Defaults to DefaultListSep.
+	ListSep string
+
+	cols EmployeeColumns
+	r    *reader
+}
+
+// NewEmployeeReader returns a reader of the CSV with a header row.
+func NewEmployeeReader(r io.Reader, cols EmployeeColumns) *EmployeeReader {
+	return &EmployeeReader{cols: cols, r: newReader(r)}
+}
+
+// Read returns the next employee, or io.EOF at the end. Invalid rows
+// return a *RowError, after which reading can go on. An invalid
+// header, at line 1, is returned on every call.
+func (r *EmployeeReader) Read() (*EmployeeRow, error) {
+	if err := r.r.readHeader(r.cols.Name, r.cols.Email); err != nil {
+		return nil, err
+	}
+
+	rec, err := r.r.next()
+	if err != nil {
+		return nil, err
+	}
+
+	sep := r.ListSep
+	if sep == "" {
+		sep = DefaultListSep
+	}
+
+	c := r.cols
+	row := &EmployeeRow{
+		Line: rec.line,
+		Employee: taxis99.Employee{
+			ID:           rec.int64(c.ID),
+			Name:         rec.required(c.Name),
+			Email:        rec.required(c.Email),
+			NationalID:   rec.get(c.NationalID),
+			SupervisorID: rec.int64(c.SupervisorID),
+			Enabled:      rec.bool(c.Enabled),
+			ExternalID:   rec.int64(c.ExternalID),
+			Categories:   rec.list(c.Categories, sep),
+		},
+		CostCenters: rec.list(c.CostCenters, sep),
+	}
+
+	if number := rec.get(c.PhoneNumber); number != "" {
+		row.Employee.Phone = &taxis99.Phone{
+			Number:  number,
+			Country: rec.get(c.PhoneCountry),
+		}
+	}
+
+	if rec.err != nil {
+		return nil, rec.err
+	}
+	return row, nil
+}
+
+// ReadAll reads all the valid employees. Invalid rows are
+// skipped and returned as Errors.
+func (r *EmployeeReader) ReadAll() ([]*EmployeeRow, error) {
+	var (
+		rows []*EmployeeRow
+		errs Errors
+	)
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if rowErr, ok := r.r.rowError(err); ok {
+			errs = append(errs, rowErr)
+			continue
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, row)
+	}
+
+	if len(errs) > 0 {
+		return rows, errs
+	}
+	return rows, nil
+}
+
+// EmployeeWriter writes employees as CSV.
+type EmployeeWriter struct {
+	// ListSep separates the categories and cost centers.
+	// Defaults to DefaultListSep.
+	ListSep string
+
+	cols EmployeeColumns
+	w    *writer
+}
+
+// NewEmployeeWriter returns a writer of the configured columns.
+// The header is written with the first employee, or by Flush.
+func NewEmployeeWriter(w io.Writer, cols EmployeeColumns) *EmployeeWriter {
+	return &EmployeeWriter{cols: cols, w: newWriter(w, cols.list())}
+}
+
+// Write writes the employee with the names of its cost centers.
+func (w *EmployeeWriter) Write(emp *taxis99.Employee, costCenters []string) error {
+	sep := w.ListSep
+	if sep == "" {
+		sep = DefaultListSep
+	}
+
+	c := w.cols
+	values := map[string]string{
+		c.ID:           formatInt(emp.ID),
+		c.Name:         emp.Name,
+		c.Email:        emp.Email,
+		c.NationalID:   emp.NationalID,
+		c.SupervisorID: formatInt(emp.SupervisorID),
+		c.Enabled:      strconv.FormatBool(emp.Enabled),
+		c.ExternalID:   formatInt(emp.ExternalID),
+		c.Categories:   strings.Join(emp.Categories, sep),
+		c.CostCenters:  strings.Join(costCenters, sep),
+	}
+	if emp.Phone != nil {
+		values[c.PhoneNumber] = emp.Phone.Number
+		values[c.PhoneCountry] = emp.Phone.Country
+	}
+
+	return w.w.write(values)
+}
+
+// Flush writes any buffered data.
+func (w *EmployeeWriter) Flush() error {
+	return w.w.flush()
+}
+
+// ExportEmployees writes all the employees matching the filter. The
+// cost center names are requested for each employee only if the
+// CostCenters column is set.
+func ExportEmployees(ctx context.Context, api taxis99.EmployeeAPI, w io.Writer, cols EmployeeColumns, f taxis99.Filter) error {
+	ew := NewEmployeeWriter(w, cols)
+
+	err := api.Each(ctx, f, func(emp *taxis99.Employee) error {
+		var names []string
+		if cols.CostCenters != "" {
+			ccs, err := api.FindCostCenters(ctx, emp.ID)
+			if err != nil {
+				return err
+			}
+			for _, cc := range ccs {
+				names = append(names, cc.Name)
+			}
+		}
+
+		return ew.Write(emp, names)
+	})
+	if err != nil {
+		return err
+	}
+
+	return ew.Flush()
+}
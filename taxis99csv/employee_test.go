@@ -0,0 +1,321 @@
+package taxis99csv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mobilitee-smartmob/taxis99"
+	"github.com/mobilitee-smartmob/taxis99/taxis99mock"
+)
+
+func TestEmployeeReaderRead(t *testing.T) {
+	in := "\ufeffName,EMAIL,phone,phone_country,enabled,external_id,categories,cost_centers\n" +
+		"Joe, joe@99.com ,11999999999,BRA,true,42,top; pop ,Sales;IT\n" +
+		",,,,,,,\n" +
+		"Ann,ann@99.com,,,,,,\n"
+
+	r := NewEmployeeReader(strings.NewReader(in), DefaultEmployeeColumns)
+
+	want := []*EmployeeRow{
+		{
+			Line: 2,
+			Employee: taxis99.Employee{
+				Name:       "Joe",
+				Email:      "joe@99.com",
+				Phone:      &taxis99.Phone{Number: "11999999999", Country: "BRA"},
+				Enabled:    true,
+				ExternalID: 42,
+				Categories: []string{"top", "pop"},
+			},
+			CostCenters: []string{"Sales", "IT"},
+		},
+		{
+			Line:     4,
+			Employee: taxis99.Employee{Name: "Ann", Email: "ann@99.com"},
+		},
+	}
+
+	for _, w := range want {
+		got, err := r.Read()
+		if err != nil {
+			t.Fatalf("Got error calling Read: %s; want it to be nil.", err.Error())
+		}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("Got row %+v; want %+v.", got, w)
+		}
+	}
+
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("Got error %v; want io.EOF.", err)
+	}
+}
+
+func TestEmployeeReaderColumns(t *testing.T) {
+	in := "Nome;E-mail;Matricula\nJoe;joe@99.com;7\n"
+
+	r := NewEmployeeReader(strings.NewReader(in), EmployeeColumns{
+		Name:       "nome",
+		Email:      "e-mail",
+		ExternalID: "matricula",
+	})
+	r.r.comma = ';'
+
+	got, err := r.Read()
+	if err != nil {
+		t.Fatalf("Got error calling Read: %s; want it to be nil.", err.Error())
+	}
+
+	want := taxis99.Employee{Name: "Joe", Email: "joe@99.com", ExternalID: 7}
+	if !reflect.DeepEqual(got.Employee, want) {
+		t.Errorf("Got employee %+v; want %+v.", got.Employee, want)
+	}
+}
+
+func TestEmployeeReaderRowError(t *testing.T) {
+	testCases := []struct {
+		row    string
+		column string
+		err    error
+	}{
+		{",joe@99.com,,", "name", ErrRequired},
+		{"Joe,,,", "email", ErrRequired},
+		{"Joe,joe@99.com,yes,", "enabled", nil},
+		{"Joe,joe@99.com,,x1", "external_id", nil},
+	}
+
+	for _, tc := range testCases {
+		in := "name,email,enabled,external_id\n" + tc.row + "\n"
+		r := NewEmployeeReader(strings.NewReader(in), DefaultEmployeeColumns)
+
+		_, err := r.Read()
+
+		var rowErr *RowError
+		if !errors.As(err, &rowErr) {
+			t.Fatalf("Got error %v reading '%s'; want *RowError.", err, tc.row)
+		}
+		if rowErr.Line != 2 || rowErr.Column != tc.column {
+			t.Errorf("Got error at line %d column '%s'; want line 2 column '%s'.", rowErr.Line, rowErr.Column, tc.column)
+		}
+		if tc.err != nil && !errors.Is(err, tc.err) {
+			t.Errorf("Got error %v; want %v.", err, tc.err)
+		}
+	}
+}
+
+func TestEmployeeReaderMissingColumn(t *testing.T) {
+	r := NewEmployeeReader(strings.NewReader("name,phone\nJoe,1199\n"), DefaultEmployeeColumns)
+
+	for i := 0; i < 2; i++ {
+		_, err := r.Read()
+		if !errors.Is(err, ErrMissingColumn) {
+			t.Fatalf("Got error %v; want ErrMissingColumn.", err)
+		}
+	}
+
+	rows, err := NewEmployeeReader(strings.NewReader("name\nJoe\n"), DefaultEmployeeColumns).ReadAll()
+	if !errors.Is(err, ErrMissingColumn) {
+		t.Errorf("Got error %v calling ReadAll; want ErrMissingColumn.", err)
+	}
+	if len(rows) != 0 {
+		t.Errorf("Got %d rows; want 0.", len(rows))
+	}
+}
+
+func TestEmployeeReaderHeaderParseError(t *testing.T) {
+	in := "name,em\"ail\n" +
+		"Joe,joe@99.com\n" +
+		"Ann,ann@99.com\n"
+
+	r := NewEmployeeReader(strings.NewReader(in), DefaultEmployeeColumns)
+	for i := 0; i < 2; i++ {
+		_, err := r.Read()
+
+		var rowErr *RowError
+		if !errors.As(err, &rowErr) || rowErr.Line != 1 {
+			t.Fatalf("Got error %v; want a row error at line 1.", err)
+		}
+	}
+
+	rows, err := NewEmployeeReader(strings.NewReader(in), DefaultEmployeeColumns).ReadAll()
+
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 1 {
+		t.Errorf("Got error %v calling ReadAll; want a row error at line 1.", err)
+	}
+	if len(rows) != 0 {
+		t.Errorf("Got rows %+v; want none.", rows)
+	}
+}
+
+func TestEmployeeReaderReadAll(t *testing.T) {
+	in := "name,email,supervisor_id\n" +
+		"Joe,joe@99.com,\n" +
+		"Bad,bad@99.com,boss\n" +
+		"Ann,ann@99.com,1\n"
+
+	rows, err := NewEmployeeReader(strings.NewReader(in), DefaultEmployeeColumns).ReadAll()
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Got error %v; want Errors.", err)
+	}
+	if len(errs) != 1 || errs[0].Line != 3 || errs[0].Column != "supervisor_id" {
+		t.Errorf("Got errors %v; want supervisor_id at line 3.", errs)
+	}
+
+	var names []string
+	for _, row := range rows {
+		names = append(names, row.Employee.Name)
+	}
+	if want := []string{"Joe", "Ann"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Got rows %v; want %v.", names, want)
+	}
+}
+
+func TestEmployeeReaderLines(t *testing.T) {
+	in := "name,email,national_id\n" +
+		"\n" +
+		"\"Joe\n Jr.\",joe@99.com,\n" +
+		"\n" +
+		"\"Ann \"\"A\"\"\",,\n" +
+		"Bob,\"bob@99.com\",x\n" +
+		"Bad,bad@99.com,\"1\n2\"x\n" +
+		"Eve,eve@99.com,\n"
+
+	rows, err := NewEmployeeReader(strings.NewReader(in), DefaultEmployeeColumns).ReadAll()
+
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("Got error %v; want 2 row errors.", err)
+	}
+	if errs[0].Line != 6 || errs[0].Column != "email" {
+		t.Errorf("Got error %v; want email at line 6.", errs[0])
+	}
+	// The invalid quote is on the second line of the row.
+	if errs[1].Line != 9 {
+		t.Errorf("Got error %v; want line 9.", errs[1])
+	}
+
+	want := map[string]int{"Joe\n Jr.": 3, "Bob": 7, "Eve": 10}
+	if len(rows) != len(want) {
+		t.Fatalf("Got %d rows; want %d.", len(rows), len(want))
+	}
+	for _, row := range rows {
+		if line := want[row.Employee.Name]; row.Line != line {
+			t.Errorf("Got %q at line %d; want line %d.", row.Employee.Name, row.Line, line)
+		}
+	}
+}
+
+func TestEmployeeReaderParseError(t *testing.T) {
+	in := "name,email\n" +
+		"Jo\"e,joe@99.com\n" +
+		"Ann,ann@99.com\n"
+
+	rows, err := NewEmployeeReader(strings.NewReader(in), DefaultEmployeeColumns).ReadAll()
+
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Line != 2 {
+		t.Fatalf("Got error %v; want a row error at line 2.", err)
+	}
+
+	if len(rows) != 1 || rows[0].Employee.Name != "Ann" || rows[0].Line != 3 {
+		t.Errorf("Got rows %+v; want Ann at line 3.", rows)
+	}
+}
+
+func TestEmployeeWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewEmployeeWriter(&buf, EmployeeColumns{
+		ID:          "id",
+		Name:        "name",
+		Email:       "email",
+		PhoneNumber: "phone",
+		Enabled:     "enabled",
+		Categories:  "categories",
+		CostCenters: "cost_centers",
+	})
+
+	emps := []*taxis99.Employee{
+		{ID: 1, Name: "Joe, Jr.", Email: "joe@99.com", Phone: &taxis99.Phone{Number: "1199"}, Enabled: true, Categories: []string{"top", "pop"}},
+		{ID: 2, Name: "Ann", Email: "ann@99.com"},
+	}
+	if err := w.Write(emps[0], []string{"Sales", "IT"}); err != nil {
+		t.Fatalf("Got error calling Write: %s; want it to be nil.", err.Error())
+	}
+	if err := w.Write(emps[1], nil); err != nil {
+		t.Fatalf("Got error calling Write: %s; want it to be nil.", err.Error())
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Got error calling Flush: %s; want it to be nil.", err.Error())
+	}
+
+	want := "id,name,email,phone,enabled,categories,cost_centers\n" +
+		"1,\"Joe, Jr.\",joe@99.com,1199,true,top;pop,Sales;IT\n" +
+		"2,Ann,ann@99.com,,false,,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Got CSV:\n%s\nwant:\n%s", got, want)
+	}
+
+	// The written CSV is read back with the same columns.
+	rows, err := NewEmployeeReader(&buf, DefaultEmployeeColumns).ReadAll()
+	if err != nil {
+		t.Fatalf("Got error calling ReadAll: %s; want it to be nil.", err.Error())
+	}
+	if len(rows) != 2 || rows[0].Employee.Name != "Joe, Jr." || !reflect.DeepEqual(rows[0].CostCenters, []string{"Sales", "IT"}) {
+		t.Errorf("Got rows %+v; want the written employees.", rows)
+	}
+}
+
+func TestExportEmployees(t *testing.T) {
+	m := taxis99mock.New()
+	m.Employee.FindFunc = func(ctx context.Context, f taxis99.Filter) ([]*taxis99.Employee, error) {
+		return []*taxis99.Employee{{ID: 7, Name: "Joe", Email: "joe@99.com"}}, nil
+	}
+	m.Employee.FindCostCentersFunc = func(ctx context.Context, empID int64) ([]*taxis99.CostCenter, error) {
+		if empID != 7 {
+			t.Errorf("Got cost centers of employee %d; want 7.", empID)
+		}
+		return []*taxis99.CostCenter{{ID: 1, Name: "Sales"}}, nil
+	}
+
+	var buf bytes.Buffer
+	cols := EmployeeColumns{ID: "id", Name: "name", CostCenters: "cost_centers"}
+	if err := ExportEmployees(context.Background(), m.Employee, &buf, cols, taxis99.Filter{}); err != nil {
+		t.Fatalf("Got error calling ExportEmployees: %s; want it to be nil.", err.Error())
+	}
+
+	want := "id,name,cost_centers\n7,Joe,Sales\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Got CSV:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExportEmployeesEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	cols := EmployeeColumns{Name: "name", Email: "email"}
+	if err := ExportEmployees(context.Background(), taxis99mock.New().Employee, &buf, cols, nil); err != nil {
+		t.Fatalf("Got error calling ExportEmployees: %s; want it to be nil.", err.Error())
+	}
+
+	if got, want := buf.String(), "name,email\n"; got != want {
+		t.Errorf("Got CSV %q; want only the header %q.", got, want)
+	}
+}
+
+func TestExportEmployeesError(t *testing.T) {
+	m := taxis99mock.New()
+	m.Employee.FindFunc = func(ctx context.Context, f taxis99.Filter) ([]*taxis99.Employee, error) {
+		return nil, taxis99.ErrNotFound
+	}
+
+	err := ExportEmployees(context.Background(), m.Employee, &bytes.Buffer{}, DefaultEmployeeColumns, taxis99.Filter{})
+	if !errors.Is(err, taxis99.ErrNotFound) {
+		t.Errorf("Got error %v; want ErrNotFound.", err)
+	}
+}
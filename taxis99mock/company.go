@@ -0,0 +1,48 @@
+package taxis99mock
+
+import (
+	"context"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+// CompanyAPI is the mock of taxis99.CompanyAPI.
+type CompanyAPI struct {
+	recorder
+
+	FindFunc func(ctx context.Context) ([]*taxis99.Company, error)
+	GetFunc  func(ctx context.Context, id string) (*taxis99.Company, error)
+}
+
+var _ taxis99.CompanyAPI = (*CompanyAPI)(nil)
+
+func (m *CompanyAPI) Find(ctx context.Context) ([]*taxis99.Company, error) {
+	m.record("Find", ctx)
+	if m.FindFunc != nil {
+		return m.FindFunc(ctx)
+	}
+	return nil, nil
+}
+
+// Get calls GetFunc when set, otherwise it looks up the
+// company in the ones returned by FindFunc.
+func (m *CompanyAPI) Get(ctx context.Context, id string) (*taxis99.Company, error) {
+	m.record("Get", ctx, id)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, id)
+	}
+	if m.FindFunc == nil {
+		return nil, taxis99.ErrNotFound
+	}
+
+	companies, err := m.FindFunc(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range companies {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, taxis99.ErrNotFound
+}
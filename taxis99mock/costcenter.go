@@ -0,0 +1,126 @@
+package taxis99mock
+
+import (
+	"context"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+// CostCenterAPI is the mock of taxis99.CostCenterAPI.
+type CostCenterAPI struct {
+	recorder
+
+	FindFunc    func(ctx context.Context, f taxis99.Filter) ([]*taxis99.CostCenter, error)
+	EachFunc    func(ctx context.Context, f taxis99.Filter, fn func(*taxis99.CostCenter) error) error
+	GetFunc     func(ctx context.Context, id int64) (*taxis99.CostCenter, error)
+	CreateFunc  func(ctx context.Context, newCC taxis99.CostCenter) (*taxis99.CostCenter, error)
+	UpdateFunc  func(ctx context.Context, cc taxis99.CostCenter) (*taxis99.CostCenter, error)
+	PatchFunc   func(ctx context.Context, id int64, p taxis99.CostCenterPatch) (*taxis99.CostCenter, error)
+	EnableFunc  func(ctx context.Context, id int64) (*taxis99.CostCenter, error)
+	DisableFunc func(ctx context.Context, id int64) (*taxis99.CostCenter, error)
+	RemoveFunc  func(ctx context.Context, id int64) error
+}
+
+var _ taxis99.CostCenterAPI = (*CostCenterAPI)(nil)
+
+func (m *CostCenterAPI) Find(ctx context.Context, f taxis99.Filter) ([]*taxis99.CostCenter, error) {
+	m.record("Find", ctx, f)
+	if m.FindFunc != nil {
+		return m.FindFunc(ctx, f)
+	}
+	return nil, nil
+}
+
+// Each calls EachFunc when set, otherwise it calls fn
+// for the cost centers returned by FindFunc.
+func (m *CostCenterAPI) Each(ctx context.Context, f taxis99.Filter, fn func(*taxis99.CostCenter) error) error {
+	m.record("Each", ctx, f, fn)
+	if m.EachFunc != nil {
+		return m.EachFunc(ctx, f, fn)
+	}
+	if m.FindFunc == nil {
+		return nil
+	}
+
+	ccs, err := m.FindFunc(ctx, f)
+	if err != nil {
+		return err
+	}
+	for _, cc := range ccs {
+		if err := fn(cc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get calls GetFunc when set, otherwise it looks up the
+// cost center in the ones returned by FindFunc.
+func (m *CostCenterAPI) Get(ctx context.Context, id int64) (*taxis99.CostCenter, error) {
+	m.record("Get", ctx, id)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, id)
+	}
+	if m.FindFunc == nil {
+		return nil, taxis99.ErrNotFound
+	}
+
+	ccs, err := m.FindFunc(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, cc := range ccs {
+		if cc.ID == id {
+			return cc, nil
+		}
+	}
+	return nil, taxis99.ErrNotFound
+}
+
+func (m *CostCenterAPI) Create(ctx context.Context, newCC taxis99.CostCenter) (*taxis99.CostCenter, error) {
+	m.record("Create", ctx, newCC)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, newCC)
+	}
+	return &newCC, nil
+}
+
+func (m *CostCenterAPI) Update(ctx context.Context, cc taxis99.CostCenter) (*taxis99.CostCenter, error) {
+	m.record("Update", ctx, cc)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, cc)
+	}
+	return &cc, nil
+}
+
+func (m *CostCenterAPI) Patch(ctx context.Context, id int64, p taxis99.CostCenterPatch) (*taxis99.CostCenter, error) {
+	m.record("Patch", ctx, id, p)
+	if m.PatchFunc != nil {
+		return m.PatchFunc(ctx, id, p)
+	}
+	return &taxis99.CostCenter{ID: id}, nil
+}
+
+func (m *CostCenterAPI) Enable(ctx context.Context, id int64) (*taxis99.CostCenter, error) {
+	m.record("Enable", ctx, id)
+	if m.EnableFunc != nil {
+		return m.EnableFunc(ctx, id)
+	}
+	return &taxis99.CostCenter{ID: id, Enabled: true}, nil
+}
+
+func (m *CostCenterAPI) Disable(ctx context.Context, id int64) (*taxis99.CostCenter, error) {
+	m.record("Disable", ctx, id)
+	if m.DisableFunc != nil {
+		return m.DisableFunc(ctx, id)
+	}
+	return &taxis99.CostCenter{ID: id}, nil
+}
+
+func (m *CostCenterAPI) Remove(ctx context.Context, id int64) error {
+	m.record("Remove", ctx, id)
+	if m.RemoveFunc != nil {
+		return m.RemoveFunc(ctx, id)
+	}
+	return nil
+}
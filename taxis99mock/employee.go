@@ -0,0 +1,202 @@
+package taxis99mock
+
+import (
+	"context"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+// EmployeeAPI is the mock of taxis99.EmployeeAPI.
+type EmployeeAPI struct {
+	recorder
+
+	FindFunc              func(ctx context.Context, f taxis99.Filter) ([]*taxis99.Employee, error)
+	EachFunc              func(ctx context.Context, f taxis99.Filter, fn func(*taxis99.Employee) error) error
+	GetFunc               func(ctx context.Context, id int64) (*taxis99.Employee, error)
+	FindByExternalIDFunc  func(ctx context.Context, extID int64) ([]*taxis99.Employee, error)
+	CreateFunc            func(ctx context.Context, emp taxis99.Employee, sendEmail bool) (*taxis99.Employee, error)
+	ImportFunc            func(ctx context.Context, emps []taxis99.Employee, opts taxis99.ImportOptions) *taxis99.ImportReport
+	ImportFromFunc        func(ctx context.Context, emps <-chan taxis99.Employee, opts taxis99.ImportOptions) *taxis99.ImportReport
+	UpdateFunc            func(ctx context.Context, emp taxis99.Employee) (*taxis99.Employee, error)
+	PatchFunc             func(ctx context.Context, id int64, p taxis99.EmployeePatch) (*taxis99.Employee, error)
+	PatchFieldsFunc       func(ctx context.Context, emp taxis99.Employee, mask ...string) (*taxis99.Employee, error)
+	EnableFunc            func(ctx context.Context, id int64) (*taxis99.Employee, error)
+	DisableFunc           func(ctx context.Context, id int64) (*taxis99.Employee, error)
+	RemoveFunc            func(ctx context.Context, id int64) error
+	FindCostCentersFunc   func(ctx context.Context, empID int64) ([]*taxis99.CostCenter, error)
+	UpdateCostCentersFunc func(ctx context.Context, empID int64, costCenterIDs []int64) ([]int64, error)
+}
+
+var _ taxis99.EmployeeAPI = (*EmployeeAPI)(nil)
+
+func (m *EmployeeAPI) Find(ctx context.Context, f taxis99.Filter) ([]*taxis99.Employee, error) {
+	m.record("Find", ctx, f)
+	if m.FindFunc != nil {
+		return m.FindFunc(ctx, f)
+	}
+	return nil, nil
+}
+
+// Each calls EachFunc when set, otherwise it calls fn
+// for the employees returned by FindFunc.
+func (m *EmployeeAPI) Each(ctx context.Context, f taxis99.Filter, fn func(*taxis99.Employee) error) error {
+	m.record("Each", ctx, f, fn)
+	if m.EachFunc != nil {
+		return m.EachFunc(ctx, f, fn)
+	}
+	if m.FindFunc == nil {
+		return nil
+	}
+
+	emps, err := m.FindFunc(ctx, f)
+	if err != nil {
+		return err
+	}
+	for _, e := range emps {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get calls GetFunc when set, otherwise it looks up the
+// employee in the ones returned by FindFunc.
+func (m *EmployeeAPI) Get(ctx context.Context, id int64) (*taxis99.Employee, error) {
+	m.record("Get", ctx, id)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, id)
+	}
+	if m.FindFunc == nil {
+		return nil, taxis99.ErrNotFound
+	}
+
+	emps, err := m.FindFunc(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range emps {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return nil, taxis99.ErrNotFound
+}
+
+func (m *EmployeeAPI) FindByExternalID(ctx context.Context, extID int64) ([]*taxis99.Employee, error) {
+	m.record("FindByExternalID", ctx, extID)
+	if m.FindByExternalIDFunc != nil {
+		return m.FindByExternalIDFunc(ctx, extID)
+	}
+	return nil, nil
+}
+
+func (m *EmployeeAPI) Create(ctx context.Context, emp taxis99.Employee, sendEmail bool) (*taxis99.Employee, error) {
+	m.record("Create", ctx, emp, sendEmail)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, emp, sendEmail)
+	}
+	return &emp, nil
+}
+
+// Import calls ImportFunc when set, otherwise
+// it calls Create for each employee.
+func (m *EmployeeAPI) Import(ctx context.Context, emps []taxis99.Employee, opts taxis99.ImportOptions) *taxis99.ImportReport {
+	m.record("Import", ctx, emps, opts)
+	if m.ImportFunc != nil {
+		return m.ImportFunc(ctx, emps, opts)
+	}
+
+	report := new(taxis99.ImportReport)
+	for i, emp := range emps {
+		report.Results = append(report.Results, m.importRow(ctx, i, emp, opts))
+	}
+	return report
+}
+
+// ImportFrom calls ImportFromFunc when set, otherwise
+// it calls Create for each employee received.
+func (m *EmployeeAPI) ImportFrom(ctx context.Context, emps <-chan taxis99.Employee, opts taxis99.ImportOptions) *taxis99.ImportReport {
+	m.record("ImportFrom", ctx, emps, opts)
+	if m.ImportFromFunc != nil {
+		return m.ImportFromFunc(ctx, emps, opts)
+	}
+
+	report := new(taxis99.ImportReport)
+	i := 0
+	for emp := range emps {
+		report.Results = append(report.Results, m.importRow(ctx, i, emp, opts))
+		i++
+	}
+	return report
+}
+
+func (m *EmployeeAPI) importRow(ctx context.Context, i int, emp taxis99.Employee, opts taxis99.ImportOptions) taxis99.ImportResult {
+	res := taxis99.ImportResult{Index: i, Employee: emp}
+	res.Created, res.Err = m.Create(ctx, emp, opts.SendWelcomeEmail)
+	return res
+}
+
+func (m *EmployeeAPI) Update(ctx context.Context, emp taxis99.Employee) (*taxis99.Employee, error) {
+	m.record("Update", ctx, emp)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, emp)
+	}
+	return &emp, nil
+}
+
+func (m *EmployeeAPI) Patch(ctx context.Context, id int64, p taxis99.EmployeePatch) (*taxis99.Employee, error) {
+	m.record("Patch", ctx, id, p)
+	if m.PatchFunc != nil {
+		return m.PatchFunc(ctx, id, p)
+	}
+	return &taxis99.Employee{ID: id}, nil
+}
+
+func (m *EmployeeAPI) PatchFields(ctx context.Context, emp taxis99.Employee, mask ...string) (*taxis99.Employee, error) {
+	m.record("PatchFields", ctx, emp, mask)
+	if m.PatchFieldsFunc != nil {
+		return m.PatchFieldsFunc(ctx, emp, mask...)
+	}
+	return &emp, nil
+}
+
+func (m *EmployeeAPI) Enable(ctx context.Context, id int64) (*taxis99.Employee, error) {
+	m.record("Enable", ctx, id)
+	if m.EnableFunc != nil {
+		return m.EnableFunc(ctx, id)
+	}
+	return &taxis99.Employee{ID: id, Enabled: true}, nil
+}
+
+func (m *EmployeeAPI) Disable(ctx context.Context, id int64) (*taxis99.Employee, error) {
+	m.record("Disable", ctx, id)
+	if m.DisableFunc != nil {
+		return m.DisableFunc(ctx, id)
+	}
+	return &taxis99.Employee{ID: id}, nil
+}
+
+func (m *EmployeeAPI) Remove(ctx context.Context, id int64) error {
+	m.record("Remove", ctx, id)
+	if m.RemoveFunc != nil {
+		return m.RemoveFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *EmployeeAPI) FindCostCenters(ctx context.Context, empID int64) ([]*taxis99.CostCenter, error) {
+	m.record("FindCostCenters", ctx, empID)
+	if m.FindCostCentersFunc != nil {
+		return m.FindCostCentersFunc(ctx, empID)
+	}
+	return nil, nil
+}
+
+func (m *EmployeeAPI) UpdateCostCenters(ctx context.Context, empID int64, costCenterIDs []int64) ([]int64, error) {
+	m.record("UpdateCostCenters", ctx, empID, costCenterIDs)
+	if m.UpdateCostCentersFunc != nil {
+		return m.UpdateCostCentersFunc(ctx, empID, costCenterIDs)
+	}
+	return costCenterIDs, nil
+}
@@ -0,0 +1,102 @@
+// Package taxis99mock provides in-memory mocks of the taxis99 service
+// interfaces. Each method calls its Func field, when set, and records
+// the call with its arguments.
+//
+//	m := taxis99mock.New()
+//	m.Employee.CreateFunc = func(ctx context.Context, emp taxis99.Employee, sendEmail bool) (*taxis99.Employee, error) {
+//		emp.ID = 1
+//		return &emp, nil
+//	}
+//
+//	provision(ctx, m) // accepts a taxis99.API
+//
+//	calls := m.Employee.Calls("Create")
+package taxis99mock
+
+import (
+	"sync"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+// Call is a recorded method call.
+type Call struct {
+	Method string
+	// Args are the method arguments, including the context.
+	Args []interface{}
+}
+
+// recorder records the calls of a mock.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, Call{method, args})
+}
+
+// Calls returns the recorded calls of the methods,
+// or all the calls if no method is given.
+func (r *recorder) Calls(methods ...string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var calls []Call
+	for _, c := range r.calls {
+		if len(methods) == 0 || contains(methods, c.Method) {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset removes the recorded calls.
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = nil
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// API is the mock of taxis99.API.
+type API struct {
+	Company    *CompanyAPI
+	CostCenter *CostCenterAPI
+	Employee   *EmployeeAPI
+}
+
+var _ taxis99.API = (*API)(nil)
+
+// New returns an API with all the service mocks.
+func New() *API {
+	return &API{
+		Company:    new(CompanyAPI),
+		CostCenter: new(CostCenterAPI),
+		Employee:   new(EmployeeAPI),
+	}
+}
+
+func (m *API) Companies() taxis99.CompanyAPI {
+	return m.Company
+}
+
+func (m *API) CostCenters() taxis99.CostCenterAPI {
+	return m.CostCenter
+}
+
+func (m *API) Employees() taxis99.EmployeeAPI {
+	return m.Employee
+}
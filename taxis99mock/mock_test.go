@@ -0,0 +1,160 @@
+package taxis99mock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+// provision is an example of consumer code depending on taxis99.API.
+func provision(ctx context.Context, api taxis99.API, emp taxis99.Employee, ccName string) error {
+	cc, err := api.CostCenters().Create(ctx, taxis99.CostCenter{Name: ccName})
+	if err != nil {
+		return err
+	}
+
+	e, err := api.Employees().Create(ctx, emp, true)
+	if err != nil {
+		return err
+	}
+
+	_, err = api.Employees().UpdateCostCenters(ctx, e.ID, []int64{cc.ID})
+	return err
+}
+
+func TestAPI(t *testing.T) {
+	m := New()
+	m.CostCenter.CreateFunc = func(ctx context.Context, cc taxis99.CostCenter) (*taxis99.CostCenter, error) {
+		cc.ID = 10
+		return &cc, nil
+	}
+	m.Employee.CreateFunc = func(ctx context.Context, emp taxis99.Employee, sendEmail bool) (*taxis99.Employee, error) {
+		emp.ID = 20
+		return &emp, nil
+	}
+
+	ctx := context.Background()
+	emp := taxis99.Employee{Name: "José"}
+
+	if err := provision(ctx, m, emp, "IT"); err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	testCases := []struct {
+		calls []Call
+		want  []Call
+	}{
+		{
+			m.CostCenter.Calls(),
+			[]Call{{"Create", []interface{}{ctx, taxis99.CostCenter{Name: "IT"}}}},
+		},
+		{
+			m.Employee.Calls("Create"),
+			[]Call{{"Create", []interface{}{ctx, emp, true}}},
+		},
+		{
+			m.Employee.Calls("UpdateCostCenters"),
+			[]Call{{"UpdateCostCenters", []interface{}{ctx, int64(20), []int64{10}}}},
+		},
+		{
+			m.Company.Calls(),
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(tc.calls, tc.want) {
+			t.Errorf("Got calls %+v; want %+v.", tc.calls, tc.want)
+		}
+	}
+
+	m.Employee.Reset()
+	if calls := m.Employee.Calls(); len(calls) != 0 {
+		t.Errorf("Got calls %+v after Reset; want none.", calls)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	m := New()
+	want := errors.New("Error!")
+	m.Employee.CreateFunc = func(ctx context.Context, emp taxis99.Employee, sendEmail bool) (*taxis99.Employee, error) {
+		return nil, want
+	}
+
+	if err := provision(context.Background(), m, taxis99.Employee{}, "IT"); err != want {
+		t.Errorf("Got error '%v'; want '%v'.", err, want)
+	}
+
+	if calls := m.Employee.Calls("UpdateCostCenters"); len(calls) != 0 {
+		t.Errorf("Got calls %+v; want none.", calls)
+	}
+}
+
+func TestEmployeeAPIEach(t *testing.T) {
+	m := New()
+	m.Employee.FindFunc = func(ctx context.Context, f taxis99.Filter) ([]*taxis99.Employee, error) {
+		return []*taxis99.Employee{{ID: 1}, {ID: 2}}, nil
+	}
+
+	var got []int64
+	err := m.Employees().Each(context.Background(), nil, func(e *taxis99.Employee) error {
+		got = append(got, e.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	if want := []int64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got employee IDs %v; want %v.", got, want)
+	}
+}
+
+func TestEmployeeAPIGet(t *testing.T) {
+	m := New()
+	m.Employee.FindFunc = func(ctx context.Context, f taxis99.Filter) ([]*taxis99.Employee, error) {
+		return []*taxis99.Employee{{ID: 1}, {ID: 2}}, nil
+	}
+
+	emp, err := m.Employees().Get(context.Background(), 2)
+	if err != nil || emp.ID != 2 {
+		t.Errorf("Got employee %+v and error '%v'; want employee 2.", emp, err)
+	}
+
+	if _, err := m.Employees().Get(context.Background(), 3); !errors.Is(err, taxis99.ErrNotFound) {
+		t.Errorf("Got error '%v'; want ErrNotFound.", err)
+	}
+}
+
+func TestClientAPI(t *testing.T) {
+	c := taxis99.NewClient(nil)
+
+	var api taxis99.API = c
+	if api.Employees() != c.Employee || api.CostCenters() != c.CostCenter || api.Companies() != c.Company {
+		t.Error("Got Client services different from its fields; want the same.")
+	}
+}
+
+func TestEmployeeAPIImport(t *testing.T) {
+	m := New()
+	want := errors.New("Error!")
+	m.Employee.CreateFunc = func(ctx context.Context, emp taxis99.Employee, sendEmail bool) (*taxis99.Employee, error) {
+		if emp.Name == "" {
+			return nil, want
+		}
+		return &emp, nil
+	}
+
+	report := m.Employees().Import(context.Background(), []taxis99.Employee{{Name: "José"}, {}}, taxis99.ImportOptions{})
+
+	if len(report.Results) != 2 || report.Results[0].Err != nil || report.Results[1].Err != want {
+		t.Errorf("Got results %+v; want the second one failed.", report.Results)
+	}
+
+	if calls := m.Employee.Calls("Create"); len(calls) != 2 {
+		t.Errorf("Got %d Create calls; want 2.", len(calls))
+	}
+}
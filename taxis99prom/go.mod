@@ -0,0 +1,10 @@
+module github.com/mobilitee-smartmob/taxis99/taxis99prom
+
+go 1.13
+
+require (
+	github.com/mobilitee-smartmob/taxis99 v0.0.0
+	github.com/prometheus/client_golang v1.11.1
+)
+
+replace github.com/mobilitee-smartmob/taxis99 => ../
@@ -0,0 +1,147 @@
+// Package taxis99prom registers the taxis99 metrics on a Prometheus
+// registry. It's a module of its own, so the taxis99 module stays
+// free of dependencies.
+//
+//	metrics := taxis99.NewMetrics()
+//	client, err := taxis99.New(taxis99.WithMetrics(metrics))
+//	...
+//	prometheus.MustRegister(taxis99prom.NewCollector(metrics))
+package taxis99prom
+
+import (
+	"strconv"
+
+	"github.com/mobilitee-smartmob/taxis99"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector adapts a taxis99.Collector, like *taxis99.Metrics,
+// to a prometheus.Collector.
+//
+// It's an unchecked collector: Describe sends no descriptors, as the
+// label values of the metrics are only known when collecting them.
+type Collector struct {
+	c taxis99.Collector
+}
+
+// NewCollector returns the prometheus.Collector of c.
+func NewCollector(c taxis99.Collector) *Collector {
+	return &Collector{c: c}
+}
+
+// Describe sends no descriptors. See Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {}
+
+// Collect sends the metrics collected by the taxis99.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, f := range c.c.Collect() {
+		if f.Type == "histogram" {
+			collectHistograms(ch, f)
+			continue
+		}
+
+		valueType := prometheus.UntypedValue
+		switch f.Type {
+		case "counter":
+			valueType = prometheus.CounterValue
+		case "gauge":
+			valueType = prometheus.GaugeValue
+		}
+
+		for _, s := range f.Samples {
+			names, values := splitLabels(s.Labels)
+			desc := prometheus.NewDesc(s.Name, f.Help, names, nil)
+			ch <- constMetric(desc, valueType, s.Value, values)
+		}
+	}
+}
+
+func constMetric(desc *prometheus.Desc, t prometheus.ValueType, v float64, labels []string) prometheus.Metric {
+	m, err := prometheus.NewConstMetric(desc, t, v, labels...)
+	if err != nil {
+		return prometheus.NewInvalidMetric(desc, err)
+	}
+	return m
+}
+
+// histogram is a histogram of the family rebuilt from its
+// _bucket, _sum and _count samples.
+type histogram struct {
+	labels  []taxis99.Label
+	buckets map[float64]uint64
+	sum     float64
+	count   uint64
+}
+
+func collectHistograms(ch chan<- prometheus.Metric, f taxis99.MetricFamily) {
+	var (
+		order []string
+		hists = map[string]*histogram{}
+	)
+	get := func(labels []taxis99.Label) *histogram {
+		key := labelsKey(labels)
+		h, ok := hists[key]
+		if !ok {
+			h = &histogram{labels: labels, buckets: map[float64]uint64{}}
+			hists[key] = h
+			order = append(order, key)
+		}
+		return h
+	}
+
+	for _, s := range f.Samples {
+		switch s.Name {
+		case f.Name + "_bucket":
+			var (
+				labels []taxis99.Label
+				le     string
+			)
+			for _, l := range s.Labels {
+				if l.Name == "le" {
+					le = l.Value
+					continue
+				}
+				labels = append(labels, l)
+			}
+
+			// The +Inf bucket is the count.
+			b, err := strconv.ParseFloat(le, 64)
+			if err == nil && le != "+Inf" {
+				get(labels).buckets[b] = uint64(s.Value)
+			}
+		case f.Name + "_sum":
+			get(s.Labels).sum = s.Value
+		case f.Name + "_count":
+			get(s.Labels).count = uint64(s.Value)
+		}
+	}
+
+	for _, key := range order {
+		h := hists[key]
+		names, values := splitLabels(h.labels)
+		desc := prometheus.NewDesc(f.Name, f.Help, names, nil)
+
+		m, err := prometheus.NewConstHistogram(desc, h.count, h.sum, h.buckets, values...)
+		if err != nil {
+			m = prometheus.NewInvalidMetric(desc, err)
+		}
+		ch <- m
+	}
+}
+
+func splitLabels(labels []taxis99.Label) ([]string, []string) {
+	names := make([]string, len(labels))
+	values := make([]string, len(labels))
+	for i, l := range labels {
+		names[i], values[i] = l.Name, l.Value
+	}
+	return names, values
+}
+
+func labelsKey(labels []taxis99.Label) string {
+	var key string
+	for _, l := range labels {
+		key += l.Name + "=" + strconv.Quote(l.Value) + ","
+	}
+	return key
+}
@@ -0,0 +1,99 @@
+package taxis99prom
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mobilitee-smartmob/taxis99"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type collectorFunc func() []taxis99.MetricFamily
+
+func (fn collectorFunc) Collect() []taxis99.MetricFamily {
+	return fn()
+}
+
+func labels(kv ...string) []taxis99.Label {
+	var ls []taxis99.Label
+	for i := 0; i < len(kv); i += 2 {
+		ls = append(ls, taxis99.Label{Name: kv[i], Value: kv[i+1]})
+	}
+	return ls
+}
+
+func TestCollector(t *testing.T) {
+	c := collectorFunc(func() []taxis99.MetricFamily {
+		return []taxis99.MetricFamily{
+			{
+				Name: "taxis99_requests_total",
+				Help: "Total of API calls.",
+				Type: "counter",
+				Samples: []taxis99.Sample{
+					{Name: "taxis99_requests_total", Labels: labels("operation", "Employee.Create", "status_class", "error"), Value: 3},
+				},
+			},
+			{
+				Name: "taxis99_request_duration_seconds",
+				Help: "Latency of the API calls.",
+				Type: "histogram",
+				Samples: []taxis99.Sample{
+					{Name: "taxis99_request_duration_seconds_bucket", Labels: labels("operation", "Employee.Find", "le", "0.1"), Value: 1},
+					{Name: "taxis99_request_duration_seconds_bucket", Labels: labels("operation", "Employee.Find", "le", "1"), Value: 2},
+					{Name: "taxis99_request_duration_seconds_bucket", Labels: labels("operation", "Employee.Find", "le", "+Inf"), Value: 3},
+					{Name: "taxis99_request_duration_seconds_sum", Labels: labels("operation", "Employee.Find"), Value: 2.5},
+					{Name: "taxis99_request_duration_seconds_count", Labels: labels("operation", "Employee.Find"), Value: 3},
+				},
+			},
+		}
+	})
+
+	want := `
+# HELP taxis99_request_duration_seconds Latency of the API calls.
+# TYPE taxis99_request_duration_seconds histogram
+taxis99_request_duration_seconds_bucket{operation="Employee.Find",le="0.1"} 1
+taxis99_request_duration_seconds_bucket{operation="Employee.Find",le="1"} 2
+taxis99_request_duration_seconds_bucket{operation="Employee.Find",le="+Inf"} 3
+taxis99_request_duration_seconds_sum{operation="Employee.Find"} 2.5
+taxis99_request_duration_seconds_count{operation="Employee.Find"} 3
+# HELP taxis99_requests_total Total of API calls.
+# TYPE taxis99_requests_total counter
+taxis99_requests_total{operation="Employee.Create",status_class="error"} 3
+`
+	if err := testutil.CollectAndCompare(NewCollector(c), strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollectorRegistry(t *testing.T) {
+	metrics := taxis99.NewMetrics()
+
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(NewCollector(metrics)); err != nil {
+		t.Fatalf("Got error calling Register: %s; want it to be nil.", err.Error())
+	}
+
+	next := func(ctx context.Context, call *taxis99.Call) error {
+		call.StatusCode = 201
+		return nil
+	}
+	call := &taxis99.Call{Operation: "Employee.Create", Method: "POST", Attempts: 1}
+	if err := metrics.Middleware()(next)(context.Background(), call); err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	want := `
+# HELP taxis99_requests_total Total of API calls by operation and status class.
+# TYPE taxis99_requests_total counter
+taxis99_requests_total{operation="Employee.Create",status_class="2xx"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "taxis99_requests_total"); err != nil {
+		t.Error(err)
+	}
+
+	if n, err := testutil.GatherAndCount(reg, "taxis99_request_duration_seconds"); err != nil || n != 1 {
+		t.Errorf("Got %d latency histograms and error '%v'; want 1.", n, err)
+	}
+}
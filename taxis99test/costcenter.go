@@ -0,0 +1,206 @@
+package taxis99test
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+func (s *Server) serveCostCenters(w http.ResponseWriter, r *http.Request, company *taxis99.Company, segs []string) {
+	switch {
+	case len(segs) == 1 && r.Method == http.MethodGet:
+		s.findCostCenters(w, r, company)
+	case len(segs) == 1 && r.Method == http.MethodPost:
+		s.createCostCenter(w, r, company)
+	case len(segs) == 2 && r.Method == http.MethodGet:
+		if id, ok := parseID(w, segs[1]); ok {
+			s.getCostCenter(w, company, id)
+		}
+	case len(segs) == 2 && r.Method == http.MethodPut:
+		if id, ok := parseID(w, segs[1]); ok {
+			s.updateCostCenter(w, r, company, id)
+		}
+	case len(segs) == 2 && r.Method == http.MethodPatch:
+		if id, ok := parseID(w, segs[1]); ok {
+			s.patchCostCenter(w, r, company, id)
+		}
+	case len(segs) == 2 && r.Method == http.MethodDelete:
+		if id, ok := parseID(w, segs[1]); ok {
+			s.removeCostCenter(w, company, id)
+		}
+	default:
+		writeError(w, http.StatusNotFound, "error.notFound", "Not found")
+	}
+}
+
+func (s *Server) findCostCenters(w http.ResponseWriter, r *http.Request, company *taxis99.Company) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	search := r.URL.Query().Get("search")
+
+	ccs := []*taxis99.CostCenter{}
+	for _, cc := range s.costCenters {
+		if cc.Company.ID == company.ID && contains(cc.Name, search) {
+			ccs = append(ccs, cc)
+		}
+	}
+	sort.Slice(ccs, func(i, j int) bool { return ccs[i].ID < ccs[j].ID })
+
+	start, end := paginate(r, len(ccs))
+	writeJSON(w, http.StatusOK, ccs[start:end])
+}
+
+func (s *Server) getCostCenter(w http.ResponseWriter, company *taxis99.Company, id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cc, ok := s.costCenters[id]
+	if !ok || cc.Company.ID != company.ID {
+		writeError(w, http.StatusNotFound, "error.costCenterNotFound", "Cost center not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cc)
+}
+
+func (s *Server) createCostCenter(w http.ResponseWriter, r *http.Request, company *taxis99.Company) {
+	var cc taxis99.CostCenter
+	if !decode(w, r, &cc) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if errs := s.validateCostCenter(&cc, company); len(errs) > 0 {
+		writeValidation(w, errs)
+		return
+	}
+
+	cc.ID = s.nextID()
+	cc.Company = company
+	cc.Enabled = true
+	s.costCenters[cc.ID] = &cc
+
+	writeJSON(w, http.StatusCreated, cc)
+}
+
+// updateCostCenter replaces the name and enabled status.
+func (s *Server) updateCostCenter(w http.ResponseWriter, r *http.Request, company *taxis99.Company, id int64) {
+	var cc taxis99.CostCenter
+	if !decode(w, r, &cc) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.costCenters[id]
+	if !ok || stored.Company.ID != company.ID {
+		writeError(w, http.StatusNotFound, "error.costCenterNotFound", "Cost center not found")
+		return
+	}
+
+	cc.ID = id
+	if errs := s.validateCostCenter(&cc, company); len(errs) > 0 {
+		writeValidation(w, errs)
+		return
+	}
+
+	stored.Name = cc.Name
+	stored.Enabled = cc.Enabled
+
+	writeJSON(w, http.StatusOK, stored)
+}
+
+// patchCostCenter changes only the fields sent.
+func (s *Server) patchCostCenter(w http.ResponseWriter, r *http.Request, company *taxis99.Company, id int64) {
+	var req struct {
+		Name    *string `json:"name"`
+		Enabled *bool   `json:"enabled"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.costCenters[id]
+	if !ok || stored.Company.ID != company.ID {
+		writeError(w, http.StatusNotFound, "error.costCenterNotFound", "Cost center not found")
+		return
+	}
+
+	cc := *stored
+	if req.Name != nil {
+		cc.Name = *req.Name
+	}
+	if req.Enabled != nil {
+		cc.Enabled = *req.Enabled
+	}
+
+	if errs := s.validateCostCenter(&cc, company); len(errs) > 0 {
+		writeValidation(w, errs)
+		return
+	}
+
+	stored.Name = cc.Name
+	stored.Enabled = cc.Enabled
+
+	writeJSON(w, http.StatusOK, stored)
+}
+
+func (s *Server) removeCostCenter(w http.ResponseWriter, company *taxis99.Company, id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cc, ok := s.costCenters[id]
+	if !ok || cc.Company.ID != company.ID {
+		writeError(w, http.StatusNotFound, "error.costCenterNotFound", "Cost center not found")
+		return
+	}
+
+	delete(s.costCenters, id)
+	for empID, ids := range s.empCostCenters {
+		s.empCostCenters[empID] = removeID(ids, id)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateCostCenter must be called holding the lock.
+func (s *Server) validateCostCenter(cc *taxis99.CostCenter, company *taxis99.Company) []taxis99.FieldError {
+	var errs []taxis99.FieldError
+
+	if strings.TrimSpace(cc.Name) == "" {
+		errs = append(errs, taxis99.FieldError{Code: "error.required", Field: "name", Message: "Name is required"})
+	}
+
+	for _, other := range s.costCenters {
+		if other.ID != cc.ID && other.Company.ID == company.ID && strings.EqualFold(other.Name, cc.Name) {
+			errs = append(errs, taxis99.FieldError{Code: "error.duplicated", Field: "name", Message: "Cost center already exists"})
+			break
+		}
+	}
+
+	return errs
+}
+
+func copyCostCenter(cc *taxis99.CostCenter) *taxis99.CostCenter {
+	c := *cc
+	return &c
+}
+
+func removeID(ids []int64, id int64) []int64 {
+	res := ids[:0]
+	for _, i := range ids {
+		if i != id {
+			res = append(res, i)
+		}
+	}
+	return res
+}
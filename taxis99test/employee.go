@@ -0,0 +1,345 @@
+package taxis99test
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+// reqEmployee is the employee request body.
+type reqEmployee struct {
+	Employee         *taxis99.Employee `json:"employee"`
+	SendWelcomeEmail bool              `json:"sendWelcomeEmail"`
+}
+
+func (s *Server) serveEmployees(w http.ResponseWriter, r *http.Request, company *taxis99.Company, segs []string) {
+	switch {
+	case len(segs) == 1 && r.Method == http.MethodGet:
+		s.findEmployees(w, r, company)
+	case len(segs) == 1 && r.Method == http.MethodPost:
+		s.createEmployee(w, r, company)
+	case len(segs) == 3 && segs[1] == "external-id" && r.Method == http.MethodGet:
+		if extID, ok := parseID(w, segs[2]); ok {
+			s.findEmployeesByExternalID(w, company, extID)
+		}
+	case len(segs) == 2 && r.Method == http.MethodGet:
+		if id, ok := parseID(w, segs[1]); ok {
+			s.getEmployee(w, company, id)
+		}
+	case len(segs) == 2 && r.Method == http.MethodPut:
+		if id, ok := parseID(w, segs[1]); ok {
+			s.updateEmployee(w, r, company, id)
+		}
+	case len(segs) == 2 && r.Method == http.MethodPatch:
+		if id, ok := parseID(w, segs[1]); ok {
+			s.patchEmployee(w, r, company, id)
+		}
+	case len(segs) == 2 && r.Method == http.MethodDelete:
+		if id, ok := parseID(w, segs[1]); ok {
+			s.removeEmployee(w, company, id)
+		}
+	case len(segs) == 3 && segs[2] == "costcenter" && r.Method == http.MethodGet:
+		if id, ok := parseID(w, segs[1]); ok {
+			s.findEmployeeCostCenters(w, company, id)
+		}
+	case len(segs) == 3 && segs[2] == "costcenter" && r.Method == http.MethodPatch:
+		if id, ok := parseID(w, segs[1]); ok {
+			s.updateEmployeeCostCenters(w, r, company, id)
+		}
+	default:
+		writeError(w, http.StatusNotFound, "error.notFound", "Not found")
+	}
+}
+
+func (s *Server) findEmployees(w http.ResponseWriter, r *http.Request, company *taxis99.Company) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query()
+	search, nationalID := q.Get("search"), q.Get("nationalId")
+
+	emps := []*taxis99.Employee{}
+	for _, e := range s.employees {
+		if e.Company.ID != company.ID {
+			continue
+		}
+		if nationalID != "" && e.NationalID != nationalID {
+			continue
+		}
+		if search != "" && !contains(e.Name, search) && !contains(e.Email, search) &&
+			e.NationalID != search && strconv.FormatInt(e.ExternalID, 10) != search {
+			continue
+		}
+		emps = append(emps, e)
+	}
+	sort.Slice(emps, func(i, j int) bool { return emps[i].ID < emps[j].ID })
+
+	start, end := paginate(r, len(emps))
+	writeJSON(w, http.StatusOK, emps[start:end])
+}
+
+func (s *Server) findEmployeesByExternalID(w http.ResponseWriter, company *taxis99.Company, extID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	emps := []*taxis99.Employee{}
+	for _, e := range s.employees {
+		if e.Company.ID == company.ID && e.ExternalID == extID {
+			emps = append(emps, e)
+		}
+	}
+	sort.Slice(emps, func(i, j int) bool { return emps[i].ID < emps[j].ID })
+
+	writeJSON(w, http.StatusOK, emps)
+}
+
+func (s *Server) getEmployee(w http.ResponseWriter, company *taxis99.Company, id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.employees[id]
+	if !ok || e.Company.ID != company.ID {
+		writeError(w, http.StatusNotFound, "error.employeeNotFound", "Employee not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, e)
+}
+
+func (s *Server) createEmployee(w http.ResponseWriter, r *http.Request, company *taxis99.Company) {
+	var req reqEmployee
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Employee == nil {
+		req.Employee = new(taxis99.Employee)
+	}
+	emp := req.Employee
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	emp.ID = 0
+	if errs := s.validateEmployee(emp, company); len(errs) > 0 {
+		writeValidation(w, errs)
+		return
+	}
+
+	emp.ID = s.nextID()
+	emp.Company = company
+	s.employees[emp.ID] = emp
+
+	writeJSON(w, http.StatusCreated, emp)
+}
+
+func (s *Server) updateEmployee(w http.ResponseWriter, r *http.Request, company *taxis99.Company, id int64) {
+	var req reqEmployee
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Employee == nil {
+		req.Employee = new(taxis99.Employee)
+	}
+	emp := req.Employee
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.employees[id]; !ok || old.Company.ID != company.ID {
+		writeError(w, http.StatusNotFound, "error.employeeNotFound", "Employee not found")
+		return
+	}
+
+	emp.ID = id
+	if errs := s.validateEmployee(emp, company); len(errs) > 0 {
+		writeValidation(w, errs)
+		return
+	}
+
+	emp.Company = company
+	s.employees[id] = emp
+
+	writeJSON(w, http.StatusOK, emp)
+}
+
+func (s *Server) patchEmployee(w http.ResponseWriter, r *http.Request, company *taxis99.Company, id int64) {
+	var req struct {
+		Employee map[string]json.RawMessage `json:"employee"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.employees[id]
+	if !ok || old.Company.ID != company.ID {
+		writeError(w, http.StatusNotFound, "error.employeeNotFound", "Employee not found")
+		return
+	}
+
+	emp := copyEmployee(old)
+	for field, v := range req.Employee {
+		if err := patchEmployeeField(emp, field, v); err != nil {
+			writeError(w, http.StatusBadRequest, "error.invalidJson", err.Error())
+			return
+		}
+	}
+
+	if errs := s.validateEmployee(emp, company); len(errs) > 0 {
+		writeValidation(w, errs)
+		return
+	}
+
+	s.employees[id] = emp
+
+	writeJSON(w, http.StatusOK, emp)
+}
+
+// patchEmployeeField sets the employee field from its JSON
+// value, clearing it if the value is null.
+func patchEmployeeField(emp *taxis99.Employee, field string, v json.RawMessage) error {
+	if string(v) != "null" {
+		b, err := json.Marshal(map[string]json.RawMessage{field: v})
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(b, emp)
+	}
+
+	switch field {
+	case "name":
+		emp.Name = ""
+	case "email":
+		emp.Email = ""
+	case "phone":
+		emp.Phone = nil
+	case "nationalId":
+		emp.NationalID = ""
+	case "supervisorId":
+		emp.SupervisorID = 0
+	case "enabled":
+		emp.Enabled = false
+	case "externalId":
+		emp.ExternalID = 0
+	case "categories":
+		emp.Categories = nil
+	}
+	return nil
+}
+
+func (s *Server) removeEmployee(w http.ResponseWriter, company *taxis99.Company, id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.employees[id]; !ok || e.Company.ID != company.ID {
+		writeError(w, http.StatusNotFound, "error.employeeNotFound", "Employee not found")
+		return
+	}
+
+	delete(s.employees, id)
+	delete(s.empCostCenters, id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) findEmployeeCostCenters(w http.ResponseWriter, company *taxis99.Company, id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.employees[id]; !ok || e.Company.ID != company.ID {
+		writeError(w, http.StatusNotFound, "error.employeeNotFound", "Employee not found")
+		return
+	}
+
+	ccs := []*taxis99.CostCenter{}
+	for _, ccID := range s.empCostCenters[id] {
+		ccs = append(ccs, s.costCenters[ccID])
+	}
+
+	writeJSON(w, http.StatusOK, ccs)
+}
+
+func (s *Server) updateEmployeeCostCenters(w http.ResponseWriter, r *http.Request, company *taxis99.Company, id int64) {
+	var req struct {
+		CostCenterIDs []int64 `json:"costCenterIDs"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.employees[id]; !ok || e.Company.ID != company.ID {
+		writeError(w, http.StatusNotFound, "error.employeeNotFound", "Employee not found")
+		return
+	}
+
+	var errs []taxis99.FieldError
+	for _, ccID := range req.CostCenterIDs {
+		if cc, ok := s.costCenters[ccID]; !ok || cc.Company.ID != company.ID {
+			errs = append(errs, taxis99.FieldError{
+				Code:    "error.costCenterNotFound",
+				Field:   "costCenterIDs",
+				Message: "Cost center " + strconv.FormatInt(ccID, 10) + " not found",
+			})
+		}
+	}
+	if len(errs) > 0 {
+		writeValidation(w, errs)
+		return
+	}
+
+	ids := append([]int64{}, req.CostCenterIDs...)
+	s.empCostCenters[id] = ids
+
+	writeJSON(w, http.StatusOK, ids)
+}
+
+// validateEmployee must be called holding the lock.
+func (s *Server) validateEmployee(emp *taxis99.Employee, company *taxis99.Company) []taxis99.FieldError {
+	var errs []taxis99.FieldError
+
+	if strings.TrimSpace(emp.Name) == "" {
+		errs = append(errs, taxis99.FieldError{Code: "error.required", Field: "employee.name", Message: "Name is required"})
+	}
+	if !strings.Contains(emp.Email, "@") {
+		errs = append(errs, taxis99.FieldError{Code: "error.invalidEmail", Field: "employee.email", Message: "error.invalidEmail"})
+	}
+	if emp.Phone == nil || len(emp.Phone.Number) < 8 {
+		errs = append(errs, taxis99.FieldError{Code: "error.invalidPhoneNumber", Field: "employee.phone", Message: "error.invalidPhoneNumber"})
+	}
+
+	for _, other := range s.employees {
+		if other.ID == emp.ID || other.Company.ID != company.ID {
+			continue
+		}
+		if emp.Email != "" && strings.EqualFold(other.Email, emp.Email) {
+			errs = append(errs, taxis99.FieldError{Code: "error.duplicatedEmail", Field: "employee.email", Message: "error.duplicatedEmail"})
+		}
+		if emp.NationalID != "" && other.NationalID == emp.NationalID {
+			errs = append(errs, taxis99.FieldError{Code: "error.duplicatedNationalId", Field: "employee.nationalId", Message: "error.duplicatedNationalId"})
+		}
+		if emp.ExternalID != 0 && other.ExternalID == emp.ExternalID {
+			errs = append(errs, taxis99.FieldError{Code: "error.duplicatedExternalId", Field: "employee.externalId", Message: "error.duplicatedExternalId"})
+		}
+	}
+
+	return errs
+}
+
+func copyEmployee(e *taxis99.Employee) *taxis99.Employee {
+	c := *e
+	if e.Phone != nil {
+		p := *e.Phone
+		c.Phone = &p
+	}
+	c.Categories = append([]string(nil), e.Categories...)
+	return &c
+}
@@ -0,0 +1,415 @@
+// Package taxis99test provides an in-memory fake of the 99 corporate
+// API for testing code that depends on the taxis99 package offline.
+//
+//	srv := taxis99test.NewServer("api-key")
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	emp, err := client.Employee.Create(ctx, taxis99.Employee{...}, false)
+package taxis99test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+const (
+	headerAPIKey    = "X-Api-Key"
+	headerCompanyID = "X-Company-Id"
+
+	headerIdempotencyKey = "Idempotency-Key"
+
+	defaultLimit = 100
+)
+
+// DefaultCompany is the company used when the
+// request has no X-Company-Id header.
+var DefaultCompany = taxis99.Company{
+	ID:   "00000000-0000-0000-0000-000000000099",
+	Name: "99 Test",
+}
+
+// Fault is an error injected in the responses of the server.
+type Fault struct {
+	// Method and Path select the requests to fail. Empty
+	// values match any request. Path is matched as a prefix
+	// of the request path, without the leading slash.
+	Method string
+	Path   string
+
+	// Status is the response status code. Defaults to 500.
+	Status int
+	// Body is the response body.
+	Body string
+	// Delay is applied before answering the request.
+	Delay time.Duration
+	// Times is the number of requests to fail. Zero fails all of them.
+	Times int
+	// After applies the request before answering with the fault,
+	// like a response lost after the API handled the request.
+	After bool
+}
+
+func (f *Fault) match(r *http.Request) bool {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	return (f.Method == "" || f.Method == r.Method) && strings.HasPrefix(path, f.Path)
+}
+
+// Server is a stateful fake of the 99 API. It implements companies,
+// cost centers and employees with in-memory storage, checks the API key
+// and company headers and answers 422 for invalid entities. POST
+// requests with an Idempotency-Key are applied once.
+type Server struct {
+	*httptest.Server
+
+	// Key is the expected API key.
+	Key string
+
+	mu             sync.Mutex
+	lastID         int64
+	companies      []*taxis99.Company
+	costCenters    map[int64]*taxis99.CostCenter
+	employees      map[int64]*taxis99.Employee
+	empCostCenters map[int64][]int64
+	faults         []*Fault
+	idempotent     map[string]*httptest.ResponseRecorder
+}
+
+// NewServer starts and returns a new Server expecting the API key.
+// The caller should call Close when finished, to shut it down.
+func NewServer(key string) *Server {
+	company := DefaultCompany
+
+	s := &Server{
+		Key:            key,
+		companies:      []*taxis99.Company{&company},
+		costCenters:    map[int64]*taxis99.CostCenter{},
+		employees:      map[int64]*taxis99.Employee{},
+		empCostCenters: map[int64][]int64{},
+		idempotent:     map[string]*httptest.ResponseRecorder{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Client returns a *taxis99.Client connected to the server
+// with its API key. Options are applied after the server ones.
+func (s *Server) Client(opts ...taxis99.Option) *taxis99.Client {
+	opts = append([]taxis99.Option{
+		taxis99.WithHTTPClient(s.Server.Client()),
+		taxis99.WithAPIKey(s.Key),
+		taxis99.WithBaseURL(s.URL),
+	}, opts...)
+
+	c, err := taxis99.New(opts...)
+	if err != nil {
+		panic("taxis99test: " + err.Error())
+	}
+	return c
+}
+
+// AddCompany adds a company the API key has access to.
+func (s *Server) AddCompany(c taxis99.Company) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.companies = append(s.companies, &c)
+}
+
+// AddCostCenter stores the cost center, without validating it,
+// and returns it with its ID. It belongs to the DefaultCompany
+// unless Company is set.
+func (s *Server) AddCostCenter(cc taxis99.CostCenter) *taxis99.CostCenter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cc.Company == nil {
+		cc.Company = s.companies[0]
+	}
+	cc.ID = s.nextID()
+	s.costCenters[cc.ID] = &cc
+
+	return copyCostCenter(&cc)
+}
+
+// AddEmployee stores the employee, without validating it,
+// and returns it with its ID. It belongs to the DefaultCompany
+// unless Company is set.
+func (s *Server) AddEmployee(e taxis99.Employee) *taxis99.Employee {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.Company == nil {
+		e.Company = s.companies[0]
+	}
+	e.ID = s.nextID()
+	s.employees[e.ID] = &e
+
+	return copyEmployee(&e)
+}
+
+// CostCenters returns all the stored cost centers ordered by ID.
+func (s *Server) CostCenters() []*taxis99.CostCenter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ccs := make([]*taxis99.CostCenter, 0, len(s.costCenters))
+	for _, cc := range s.costCenters {
+		ccs = append(ccs, copyCostCenter(cc))
+	}
+	sort.Slice(ccs, func(i, j int) bool { return ccs[i].ID < ccs[j].ID })
+
+	return ccs
+}
+
+// Employees returns all the stored employees ordered by ID.
+func (s *Server) Employees() []*taxis99.Employee {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	emps := make([]*taxis99.Employee, 0, len(s.employees))
+	for _, e := range s.employees {
+		emps = append(emps, copyEmployee(e))
+	}
+	sort.Slice(emps, func(i, j int) bool { return emps[i].ID < emps[j].ID })
+
+	return emps
+}
+
+// EmployeeCostCenters returns the cost center IDs assigned to the employee.
+func (s *Server) EmployeeCostCenters(empID int64) []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]int64(nil), s.empCostCenters[empID]...)
+}
+
+// Inject adds a fault to the server responses. Faults are
+// checked in the order they were injected.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// Reset removes all the stored data and injected faults.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.companies = s.companies[:1]
+	s.costCenters = map[int64]*taxis99.CostCenter{}
+	s.employees = map[int64]*taxis99.Employee{}
+	s.empCostCenters = map[int64][]int64{}
+	s.faults = nil
+	s.idempotent = map[string]*httptest.ResponseRecorder{}
+}
+
+// nextID returns a new entity ID. Must be called holding the lock.
+func (s *Server) nextID() int64 {
+	s.lastID++
+	return s.lastID
+}
+
+// fault returns the fault matching the request, if any.
+func (s *Server) fault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.faults {
+		if !f.match(r) {
+			continue
+		}
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// company returns the company of the request.
+func (s *Server) company(r *http.Request) (*taxis99.Company, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.Header.Get(headerCompanyID)
+	if id == "" {
+		return s.companies[0], true
+	}
+
+	for _, c := range s.companies {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if f := s.fault(r); f != nil {
+		if f.After {
+			s.serveIdempotent(httptest.NewRecorder(), r)
+		}
+		if f.Delay > 0 {
+			select {
+			case <-time.After(f.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		status := f.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(f.Body))
+		return
+	}
+
+	s.serveIdempotent(w, r)
+}
+
+// serveIdempotent replays the response of the POST requests
+// with an Idempotency-Key already handled successfully.
+func (s *Server) serveIdempotent(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get(headerIdempotencyKey)
+	if r.Method != http.MethodPost || key == "" {
+		s.serve(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	rec, ok := s.idempotent[key]
+	s.mu.Unlock()
+
+	if !ok {
+		rec = httptest.NewRecorder()
+		s.serve(rec, r)
+
+		if rec.Code < http.StatusBadRequest {
+			s.mu.Lock()
+			s.idempotent[key] = rec
+			s.mu.Unlock()
+		}
+	}
+
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.Code)
+	w.Write(rec.Body.Bytes())
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get(headerAPIKey) != s.Key {
+		writeError(w, http.StatusUnauthorized, "error.invalidApiKey", "Invalid API key")
+		return
+	}
+
+	company, ok := s.company(r)
+	if !ok {
+		writeError(w, http.StatusForbidden, "error.invalidCompany", "Company not allowed")
+		return
+	}
+
+	segs := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch segs[0] {
+	case "companies":
+		s.serveCompanies(w, r, segs)
+	case "costcenters":
+		s.serveCostCenters(w, r, company, segs)
+	case "employees":
+		s.serveEmployees(w, r, company, segs)
+	default:
+		writeError(w, http.StatusNotFound, "error.notFound", "Not found")
+	}
+}
+
+func (s *Server) serveCompanies(w http.ResponseWriter, r *http.Request, segs []string) {
+	if len(segs) != 1 || r.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "error.notFound", "Not found")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, s.companies)
+}
+
+// parseID parses the entity ID from the path segment.
+func parseID(w http.ResponseWriter, seg string) (int64, bool) {
+	id, err := strconv.ParseInt(seg, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "error.notFound", "Not found")
+		return 0, false
+	}
+	return id, true
+}
+
+// paginate returns the page of n items selected by the
+// limit and page query params as slice bounds.
+func paginate(r *http.Request, n int) (int, int) {
+	q := r.URL.Query()
+
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultLimit
+	}
+	page, err := strconv.Atoi(q.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	start := (page - 1) * limit
+	if start > n {
+		start = n
+	}
+	end := start + limit
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+func contains(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "error.invalidJson", err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, taxis99.FieldError{Code: code, Message: msg})
+}
+
+// writeValidation answers 422 with the field errors.
+func writeValidation(w http.ResponseWriter, errs []taxis99.FieldError) {
+	writeJSON(w, http.StatusUnprocessableEntity, struct {
+		Errors []taxis99.FieldError `json:"errors"`
+	}{errs})
+}
@@ -0,0 +1,442 @@
+package taxis99test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+func newEmployee(name string, extID int64) taxis99.Employee {
+	return taxis99.Employee{
+		Name:  name,
+		Email: name + "@empresa.com.br",
+		Phone: &taxis99.Phone{
+			Number:  "11999999999",
+			Country: "BRA",
+		},
+		NationalID: "123" + name,
+		ExternalID: extID,
+		Enabled:    true,
+		Categories: []string{"pop99"},
+	}
+}
+
+func TestServerCompany(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	companies, err := srv.Client().Company.Find(context.Background())
+	if err != nil {
+		t.Fatalf("Got error calling Company.Find: %s; want nil.", err.Error())
+	}
+
+	if want := []*taxis99.Company{&DefaultCompany}; !reflect.DeepEqual(companies, want) {
+		t.Errorf("Got companies %+v; want %+v.", companies, want)
+	}
+}
+
+func TestServerCostCenter(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	c := srv.Client()
+	ctx := context.Background()
+
+	cc, err := c.CostCenter.Create(ctx, taxis99.CostCenter{Name: "IT"})
+	if err != nil {
+		t.Fatalf("Got error calling CostCenter.Create: %s; want nil.", err.Error())
+	}
+
+	if cc.ID == 0 || !cc.Enabled || cc.Company == nil {
+		t.Errorf("Got cost center %+v; want it with ID, enabled and company.", cc)
+	}
+
+	if got, err := c.CostCenter.Get(ctx, cc.ID); err != nil || got.Name != "IT" {
+		t.Errorf("Got cost center %+v and error '%v' calling CostCenter.Get; want IT.", got, err)
+	}
+
+	sales := srv.AddCostCenter(taxis99.CostCenter{Name: "Sales"})
+
+	_, err = c.CostCenter.Update(ctx, taxis99.CostCenter{ID: cc.ID, Name: "sales", Enabled: true})
+	var valErr *taxis99.ValidationError
+	if !errors.As(err, &valErr) {
+		t.Errorf("Got error '%v' renaming to an existing name; want ValidationError.", err)
+	}
+
+	// Patching the name keeps the cost center enabled.
+	var p taxis99.CostCenterPatch
+	p.Name.Set("IT2")
+	if got, err := c.CostCenter.Patch(ctx, cc.ID, p); err != nil || got.Name != "IT2" || !got.Enabled {
+		t.Errorf("Got cost center %+v and error '%v' patching its name; want IT2 enabled.", got, err)
+	}
+
+	// Update replaces the whole cost center.
+	if got, err := c.CostCenter.Update(ctx, taxis99.CostCenter{ID: cc.ID, Name: "IT3"}); err != nil || got.Name != "IT3" || got.Enabled {
+		t.Errorf("Got cost center %+v and error '%v' calling CostCenter.Update; want IT3 disabled.", got, err)
+	}
+	if got, err := c.CostCenter.Update(ctx, taxis99.CostCenter{ID: cc.ID, Name: "IT", Enabled: true}); err != nil || got.Name != "IT" || !got.Enabled {
+		t.Errorf("Got cost center %+v and error '%v' calling CostCenter.Update; want IT enabled.", got, err)
+	}
+
+	if got, err := c.CostCenter.Disable(ctx, sales.ID); err != nil || got.Enabled || got.Name != "Sales" {
+		t.Errorf("Got cost center %+v and error '%v' calling CostCenter.Disable; want Sales disabled.", got, err)
+	}
+	if got, err := c.CostCenter.Enable(ctx, sales.ID); err != nil || !got.Enabled {
+		t.Errorf("Got cost center %+v and error '%v' calling CostCenter.Enable; want it enabled.", got, err)
+	}
+
+	found, err := c.CostCenter.Find(ctx, taxis99.Filter{"search": "it"})
+	if err != nil {
+		t.Fatalf("Got error calling CostCenter.Find: %s; want nil.", err.Error())
+	}
+	if len(found) != 1 || found[0].ID != cc.ID {
+		t.Errorf("Got cost centers %+v; want only %+v.", found, cc)
+	}
+
+	if err := c.CostCenter.Remove(ctx, cc.ID); err != nil {
+		t.Fatalf("Got error calling CostCenter.Remove: %s; want nil.", err.Error())
+	}
+
+	if got := srv.CostCenters(); len(got) != 1 || got[0].Name != "Sales" {
+		t.Errorf("Got stored cost centers %+v; want only Sales.", got)
+	}
+
+	if err := c.CostCenter.Remove(ctx, cc.ID); !errors.Is(err, taxis99.ErrNotFound) {
+		t.Errorf("Got error '%v' removing twice; want ErrNotFound.", err)
+	}
+
+	if _, err := c.CostCenter.Get(ctx, cc.ID); !errors.Is(err, taxis99.ErrNotFound) {
+		t.Errorf("Got error '%v' getting a removed cost center; want ErrNotFound.", err)
+	}
+}
+
+func TestServerCostCenterValidation(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	srv.AddCostCenter(taxis99.CostCenter{Name: "IT"})
+
+	testCases := []struct {
+		cc        taxis99.CostCenter
+		wantField string
+		wantCode  string
+	}{
+		{taxis99.CostCenter{}, "name", "error.required"},
+		{taxis99.CostCenter{Name: "it"}, "name", "error.duplicated"},
+	}
+
+	for _, tc := range testCases {
+		_, err := srv.Client().CostCenter.Create(context.Background(), tc.cc)
+
+		var valErr *taxis99.ValidationError
+		if !errors.As(err, &valErr) {
+			t.Fatalf("Got error '%v'; want ValidationError.", err)
+		}
+
+		if fe, ok := valErr.Field(tc.wantField); !ok || fe.Code != tc.wantCode {
+			t.Errorf("Got field errors %+v; want %s for %s.", valErr.Errors, tc.wantCode, tc.wantField)
+		}
+	}
+}
+
+func TestServerEmployee(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	c := srv.Client()
+	ctx := context.Background()
+
+	emp, err := c.Employee.Create(ctx, newEmployee("jose", 55091), false)
+	if err != nil {
+		t.Fatalf("Got error calling Employee.Create: %s; want nil.", err.Error())
+	}
+
+	found, err := c.Employee.FindByExternalID(ctx, 55091)
+	if err != nil {
+		t.Fatalf("Got error calling Employee.FindByExternalID: %s; want nil.", err.Error())
+	}
+	if len(found) != 1 || found[0].ID != emp.ID {
+		t.Errorf("Got employees %+v; want only %+v.", found, emp)
+	}
+
+	emp.Name = "José Santos"
+	if _, err := c.Employee.Update(ctx, *emp); err != nil {
+		t.Fatalf("Got error calling Employee.Update: %s; want nil.", err.Error())
+	}
+
+	found, err = c.Employee.Find(ctx, taxis99.Filter{"search": "santos"})
+	if err != nil {
+		t.Fatalf("Got error calling Employee.Find: %s; want nil.", err.Error())
+	}
+	if len(found) != 1 || found[0].Name != "José Santos" {
+		t.Errorf("Got employees %+v; want the updated one.", found)
+	}
+
+	if got, err := c.Employee.Get(ctx, emp.ID); err != nil || got.Name != "José Santos" {
+		t.Errorf("Got employee %+v and error '%v' calling Employee.Get; want the updated one.", got, err)
+	}
+
+	if err := c.Employee.Remove(ctx, emp.ID); err != nil {
+		t.Fatalf("Got error calling Employee.Remove: %s; want nil.", err.Error())
+	}
+
+	if got := srv.Employees(); len(got) != 0 {
+		t.Errorf("Got stored employees %+v; want none.", got)
+	}
+
+	if _, err := c.Employee.Get(ctx, emp.ID); !errors.Is(err, taxis99.ErrNotFound) {
+		t.Errorf("Got error '%v' getting a removed employee; want ErrNotFound.", err)
+	}
+}
+
+func TestServerEmployeePatch(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	c := srv.Client()
+	ctx := context.Background()
+
+	sup := srv.AddEmployee(newEmployee("supervisor", 1))
+	e := newEmployee("jose", 2)
+	e.SupervisorID = sup.ID
+	emp := srv.AddEmployee(e)
+
+	got, err := c.Employee.Disable(ctx, emp.ID)
+	if err != nil {
+		t.Fatalf("Got error calling Employee.Disable: %s; want nil.", err.Error())
+	}
+	if got.Enabled || got.Name != emp.Name || got.SupervisorID != sup.ID {
+		t.Errorf("Got employee %+v; want only Enabled changed.", got)
+	}
+
+	var p taxis99.EmployeePatch
+	p.SupervisorID.Clear()
+	p.Phone.Set(taxis99.Phone{Number: "11988887777", Country: "BR"})
+	if got, err = c.Employee.Patch(ctx, emp.ID, p); err != nil {
+		t.Fatalf("Got error calling Employee.Patch: %s; want nil.", err.Error())
+	}
+	if got.SupervisorID != 0 || got.Phone.Number != "11988887777" || got.Email != emp.Email {
+		t.Errorf("Got employee %+v; want supervisor cleared and phone changed.", got)
+	}
+
+	p = taxis99.EmployeePatch{}
+	p.Phone.Clear()
+	var valErr *taxis99.ValidationError
+	if _, err := c.Employee.Patch(ctx, emp.ID, p); !errors.As(err, &valErr) {
+		t.Errorf("Got error '%v' clearing the phone; want ValidationError.", err)
+	}
+
+	if _, err := c.Employee.Enable(ctx, 0); !errors.Is(err, taxis99.ErrNotFound) {
+		t.Errorf("Got error '%v' enabling a missing employee; want ErrNotFound.", err)
+	}
+}
+
+func TestServerEmployeePagination(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	for i := int64(1); i <= 5; i++ {
+		srv.AddEmployee(newEmployee("emp", i))
+	}
+
+	var got []int64
+	err := srv.Client().Employee.Each(context.Background(), taxis99.Filter{"limit": "2"}, func(e *taxis99.Employee) error {
+		got = append(got, e.ExternalID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Got error calling Employee.Each: %s; want nil.", err.Error())
+	}
+
+	if want := []int64{1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got employees %v; want %v.", got, want)
+	}
+}
+
+func TestServerEmployeeValidation(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	srv.AddEmployee(newEmployee("jose", 1))
+
+	emp := newEmployee("maria", 2)
+	emp.Email = "invalid"
+	emp.NationalID = "123jose"
+
+	_, err := srv.Client().Employee.Create(context.Background(), emp, false)
+
+	var valErr *taxis99.ValidationError
+	if !errors.As(err, &valErr) {
+		t.Fatalf("Got error '%v'; want ValidationError.", err)
+	}
+
+	for _, field := range []string{"employee.email", "employee.nationalId"} {
+		if _, ok := valErr.Field(field); !ok {
+			t.Errorf("Got field errors %+v; want an error for %s.", valErr.Errors, field)
+		}
+	}
+}
+
+func TestServerEmployeeCostCenters(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	c := srv.Client()
+	ctx := context.Background()
+
+	emp := srv.AddEmployee(newEmployee("jose", 1))
+	cc := srv.AddCostCenter(taxis99.CostCenter{Name: "IT"})
+
+	ids, err := c.Employee.UpdateCostCenters(ctx, emp.ID, []int64{cc.ID})
+	if err != nil {
+		t.Fatalf("Got error calling Employee.UpdateCostCenters: %s; want nil.", err.Error())
+	}
+	if want := []int64{cc.ID}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Got cost center IDs %v; want %v.", ids, want)
+	}
+
+	ccs, err := c.Employee.FindCostCenters(ctx, emp.ID)
+	if err != nil {
+		t.Fatalf("Got error calling Employee.FindCostCenters: %s; want nil.", err.Error())
+	}
+	if len(ccs) != 1 || ccs[0].Name != "IT" {
+		t.Errorf("Got cost centers %+v; want IT.", ccs)
+	}
+
+	_, err = c.Employee.UpdateCostCenters(ctx, emp.ID, []int64{999})
+	var valErr *taxis99.ValidationError
+	if !errors.As(err, &valErr) {
+		t.Errorf("Got error '%v' for unknown cost center; want ValidationError.", err)
+	}
+}
+
+func TestServerAuth(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	srv.AddCompany(taxis99.Company{ID: "other", Name: "Other"})
+
+	testCases := []struct {
+		name string
+		opts []taxis99.Option
+		want error
+	}{
+		{"InvalidKey", []taxis99.Option{taxis99.WithAPIKey("invalid")}, taxis99.ErrUnauthorized},
+		{"InvalidCompany", []taxis99.Option{taxis99.WithCompanyID("invalid")}, taxis99.ErrUnauthorized},
+		{"Company", []taxis99.Option{taxis99.WithCompanyID("other")}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := srv.Client(tc.opts...).Employee.Find(context.Background(), nil)
+			if tc.want == nil && err != nil || tc.want != nil && !errors.Is(err, tc.want) {
+				t.Errorf("Got error '%v'; want '%v'.", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestServerCompanyScope(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	other := taxis99.Company{ID: "other", Name: "Other"}
+	srv.AddCompany(other)
+	srv.AddEmployee(newEmployee("jose", 1))
+
+	ctx := context.WithValue(context.Background(), taxis99.CompanyID, other.ID)
+	emps, err := srv.Client().Employee.Find(ctx, nil)
+	if err != nil {
+		t.Fatalf("Got error calling Employee.Find: %s; want nil.", err.Error())
+	}
+
+	if len(emps) != 0 {
+		t.Errorf("Got employees %+v from other company; want none.", emps)
+	}
+}
+
+func TestServerInject(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	srv.Inject(Fault{Method: http.MethodGet, Path: "employees", Status: http.StatusServiceUnavailable, Times: 2})
+
+	c := srv.Client(taxis99.WithRetryPolicy(&taxis99.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	if _, err := c.Employee.Find(context.Background(), nil); err != nil {
+		t.Errorf("Got error '%v' after retries; want nil.", err)
+	}
+
+	srv.Inject(Fault{Path: "costcenters", Status: http.StatusConflict, Body: `{"message":"conflict"}`})
+	for i := 0; i < 2; i++ {
+		if _, err := c.CostCenter.Find(context.Background(), nil); !errors.Is(err, taxis99.ErrConflict) {
+			t.Errorf("Got error '%v'; want ErrConflict.", err)
+		}
+	}
+
+	srv.Reset()
+	if _, err := c.CostCenter.Find(context.Background(), nil); err != nil {
+		t.Errorf("Got error '%v' after Reset; want nil.", err)
+	}
+}
+
+func TestServerInjectDelay(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	srv.Inject(Fault{Delay: 200 * time.Millisecond, Times: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := srv.Client().Company.Find(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Got error '%v'; want context.DeadlineExceeded.", err)
+	}
+}
+
+func TestServerInjectAfter(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	retry := taxis99.WithRetryPolicy(&taxis99.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})
+
+	testCases := []struct {
+		name string
+		opts []taxis99.Option
+		path string
+	}{
+		// The cost center is found by name before creating it again.
+		{"Dedupe", []taxis99.Option{retry}, "costcenters"},
+		// The API replays the response of the key already used.
+		{"IdempotencyKey", []taxis99.Option{retry, taxis99.WithIdempotencyKeys()}, "employees"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv.Reset()
+			srv.Inject(Fault{Method: http.MethodPost, Path: tc.path, Status: http.StatusBadGateway, Times: 1, After: true})
+
+			c := srv.Client(tc.opts...)
+
+			var err error
+			if tc.path == "costcenters" {
+				c.Retry.RetryNonIdempotent = true
+				_, err = c.CostCenter.Create(context.Background(), taxis99.CostCenter{Name: "IT"})
+			} else {
+				emp := taxis99.Employee{Name: "José", Email: "jose@example.com", Phone: &taxis99.Phone{Number: "11999999999"}}
+				_, err = c.Employee.Create(context.Background(), emp, false)
+			}
+			if err != nil {
+				t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+			}
+
+			if n := len(srv.CostCenters()) + len(srv.Employees()); n != 1 {
+				t.Errorf("Got %d entities stored; want 1.", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package taxis99test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+// Tracer is an in-memory taxis99.Tracer exporting the ended spans,
+// to assert on the tracing of the API calls. It's also a
+// taxis99.Propagator injecting the W3C traceparent header.
+type Tracer struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// Span is a span recorded by the Tracer.
+type Span struct {
+	Name     string
+	TraceID  string
+	SpanID   string
+	ParentID string
+
+	StartTime time.Time
+	EndTime   time.Time
+
+	Attributes map[string]interface{}
+	Events     []Event
+	Errors     []error
+
+	mu     sync.Mutex
+	tracer *Tracer
+}
+
+// Event is an event added to a Span.
+type Event struct {
+	Name       string
+	Attributes map[string]interface{}
+}
+
+var (
+	_ taxis99.Tracer     = (*Tracer)(nil)
+	_ taxis99.Propagator = (*Tracer)(nil)
+)
+
+type spanKey struct{}
+
+// NewTracer returns an empty Tracer.
+func NewTracer() *Tracer {
+	return new(Tracer)
+}
+
+// Start starts a span, child of the span in the context if any.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, taxis99.Span) {
+	s := &Span{
+		Name:       name,
+		TraceID:    randomID(16),
+		SpanID:     randomID(8),
+		StartTime:  time.Now(),
+		Attributes: map[string]interface{}{},
+		tracer:     t,
+	}
+
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok {
+		s.TraceID = parent.TraceID
+		s.ParentID = parent.SpanID
+	}
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Inject sets the traceparent header of the span in the context.
+func (t *Tracer) Inject(ctx context.Context, h http.Header) {
+	if s, ok := ctx.Value(spanKey{}).(*Span); ok {
+		h.Set("traceparent", fmt.Sprintf("00-%s-%s-01", s.TraceID, s.SpanID))
+	}
+}
+
+// Spans returns the ended spans, in the order they ended.
+func (t *Tracer) Spans() []*Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]*Span(nil), t.spans...)
+}
+
+// Reset removes the ended spans.
+func (t *Tracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.spans = nil
+}
+
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Attributes[key] = value
+}
+
+func (s *Span) AddEvent(name string, attrs map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Events = append(s.Events, Event{name, attrs})
+}
+
+func (s *Span) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Errors = append(s.Errors, err)
+}
+
+// End ends the span and exports it to the Tracer.
+func (s *Span) End() {
+	s.mu.Lock()
+	s.EndTime = time.Now()
+	s.mu.Unlock()
+
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.tracer.spans = append(s.tracer.spans, s)
+}
+
+func randomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,179 @@
+package taxis99test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mobilitee-smartmob/taxis99"
+)
+
+func TestTracer(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	tracer := NewTracer()
+	c := srv.Client(
+		taxis99.WithTracer(tracer),
+		taxis99.WithRetryPolicy(&taxis99.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+	)
+
+	srv.Inject(Fault{Method: http.MethodPost, Path: "costcenters", Status: http.StatusServiceUnavailable, Times: 1})
+
+	ctx, parent := tracer.Start(context.Background(), "provision")
+	if _, err := c.CostCenter.Create(ctx, taxis99.CostCenter{Name: "IT"}); err == nil {
+		t.Fatal("Got error nil; want the injected fault.")
+	}
+	if _, err := c.Employee.Find(ctx, taxis99.Filter{"search": "jose"}); err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+	parent.End()
+
+	spans := tracer.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("Got %d spans; want 3.", len(spans))
+	}
+
+	create, find := spans[0], spans[1]
+
+	if create.Name != "CostCenter.Create" || find.Name != "Employee.Find" {
+		t.Errorf("Got spans %s and %s; want CostCenter.Create and Employee.Find.", create.Name, find.Name)
+	}
+
+	for _, s := range []*Span{create, find} {
+		if s.ParentID != spans[2].SpanID || s.TraceID != spans[2].TraceID {
+			t.Errorf("Got span %s parent %s; want %s.", s.Name, s.ParentID, spans[2].SpanID)
+		}
+	}
+
+	wantAttrs := map[string]interface{}{
+		"taxis99.operation":         "CostCenter.Create",
+		"http.request.method":       http.MethodPost,
+		"url.path":                  "costcenters",
+		"http.response.status_code": http.StatusServiceUnavailable,
+		"taxis99.attempts":          1,
+	}
+	for k, want := range wantAttrs {
+		if got := create.Attributes[k]; got != want {
+			t.Errorf("Got attribute %s %v; want %v.", k, got, want)
+		}
+	}
+
+	// POST requests are not retried.
+	if len(create.Events) != 1 || len(create.Errors) != 1 || !errors.Is(create.Errors[0], taxis99.ErrServer) {
+		t.Errorf("Got events %+v and errors %v; want 1 attempt with ErrServer.", create.Events, create.Errors)
+	}
+
+	if got := find.Attributes["url.path"].(string); strings.Contains(got, "jose") {
+		t.Errorf("Got url.path '%s'; want the search redacted.", got)
+	}
+
+	if len(find.Errors) != 0 || find.Attributes["http.response.status_code"] != http.StatusOK {
+		t.Errorf("Got span %+v; want it successful.", find)
+	}
+}
+
+func TestTracerRetryAttempts(t *testing.T) {
+	srv := NewServer("key")
+	defer srv.Close()
+
+	tracer := NewTracer()
+	c := srv.Client(
+		taxis99.WithTracer(tracer),
+		taxis99.WithRetryPolicy(&taxis99.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+	)
+
+	srv.Inject(Fault{Path: "companies", Status: http.StatusBadGateway, Times: 2})
+
+	if _, err := c.Company.Find(context.Background()); err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	spans := tracer.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("Got %d spans; want 1.", len(spans))
+	}
+
+	if got := spans[0].Attributes["taxis99.attempts"]; got != 3 {
+		t.Errorf("Got attempts %v; want 3.", got)
+	}
+
+	wantStatus := []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK}
+	if len(spans[0].Events) != len(wantStatus) {
+		t.Fatalf("Got events %+v; want %d.", spans[0].Events, len(wantStatus))
+	}
+	for i, e := range spans[0].Events {
+		if e.Name != "attempt" || e.Attributes["attempt"] != i+1 || e.Attributes["http.response.status_code"] != wantStatus[i] {
+			t.Errorf("Got event %+v; want attempt %d with status %d.", e, i+1, wantStatus[i])
+		}
+	}
+}
+
+func TestTracerPropagation(t *testing.T) {
+	tracer := NewTracer()
+
+	var got string
+	srv := NewServer("key")
+	defer srv.Close()
+
+	c := srv.Client(
+		taxis99.WithTracer(tracer),
+		taxis99.WithHTTPClient(&http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			got = r.Header.Get("traceparent")
+			return http.DefaultTransport.RoundTrip(r)
+		})}),
+	)
+
+	if _, err := c.Company.Find(context.Background()); err != nil {
+		t.Fatalf("Got unexpected error '%s'; want nil.", err.Error())
+	}
+
+	s := tracer.Spans()[0]
+	if want := "00-" + s.TraceID + "-" + s.SpanID + "-01"; got != want {
+		t.Errorf("Got traceparent header '%s'; want '%s'.", got, want)
+	}
+}
+
+func TestTracerTransportError(t *testing.T) {
+	tracer := NewTracer()
+	c, err := taxis99.New(
+		taxis99.WithTracer(tracer),
+		taxis99.WithHTTPClient(&http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection reset")
+		})}),
+	)
+	if err != nil {
+		t.Fatalf("Got error calling New: %s; want it to be nil.", err.Error())
+	}
+
+	f := taxis99.Filter{"search": "jose@corp.com", "nationalId": "12345678900"}
+	if _, err := c.Employee.Find(context.Background(), f); err == nil {
+		t.Fatal("Got error nil; want the transport error.")
+	}
+
+	s := tracer.Spans()[0]
+	if len(s.Events) != 1 || len(s.Errors) != 1 {
+		t.Fatalf("Got events %+v and errors %v; want 1 of each.", s.Events, s.Errors)
+	}
+
+	traced := []string{s.Events[0].Attributes["error"].(string), s.Errors[0].Error()}
+	for _, got := range traced {
+		if !strings.Contains(got, "connection reset") {
+			t.Errorf("Got error '%s'; want the transport error.", got)
+		}
+		for _, secret := range []string{"jose@corp.com", "12345678900"} {
+			if strings.Contains(got, secret) {
+				t.Errorf("Got '%s' in the traced error '%s'; want it redacted.", secret, got)
+			}
+		}
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
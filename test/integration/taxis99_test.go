@@ -1,20 +1,20 @@
 package integration
 
 import (
-	"bytes"
 	"flag"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
 	"unsafe"
 
 	"github.com/mobilitee-smartmob/taxis99"
+	"github.com/mobilitee-smartmob/taxis99/taxis99test"
 )
 
 const (
@@ -23,6 +23,17 @@ const (
 	envKey99TaxisCompanyID  = "TAXIS99_COMPANY_ID"
 )
 
+// cassette is the file with the requests recorded by -record and
+// replayed when there's no API key, so the suite runs without network.
+// With an API key, -record records the API; without one, it records
+// the taxis99test fake server:
+//
+//	go test ./test/integration -record
+const cassette = "testdata/cassette.json"
+
+// cassetteSeed makes the random test data match the cassette.
+const cassetteSeed = 99
+
 const (
 	letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	numberBytes = "0123456789"
@@ -43,46 +54,7 @@ var src = rand.NewSource(time.Now().UnixNano())
 
 var logging = flag.Bool("log", false, "Define if tests should log the requests")
 
-// Transport used to log the requests.
-type transportLogger struct {
-	base http.RoundTripper
-}
-
-func (t *transportLogger) RoundTrip(req *http.Request) (*http.Response, error) {
-	var (
-		reqBody []byte
-		err     error
-	)
-
-	if req.Body != nil {
-		reqBody, err = ioutil.ReadAll(req.Body)
-		if err != nil {
-			return nil, err
-		}
-		req.Body.Close()
-
-		req.Body = ioutil.NopCloser(bytes.NewBuffer(reqBody))
-	}
-
-	res, err := t.base.RoundTrip(req)
-	if err != nil {
-		return nil, err
-	}
-
-	resBody, _ := ioutil.ReadAll(res.Body)
-
-	log.Println("Request")
-	log.Println("Headers:")
-	for k, v := range req.Header {
-		log.Printf("%s: %s\n", k, v)
-	}
-	log.Printf("/%s %s %s --> Response %s %s",
-		req.Method, req.URL.String(), string(reqBody), res.Status, string(resBody))
-
-	res.Body = ioutil.NopCloser(bytes.NewBuffer(resBody))
-
-	return res, nil
-}
+var recording = flag.Bool("record", false, "Define if tests should record the requests to "+cassette)
 
 func TestMain(m *testing.M) {
 	flag.Parse()
@@ -98,21 +70,79 @@ func TestMain(m *testing.M) {
 
 	companyID := os.Getenv(envKey99TaxisCompanyID)
 	apiKey := os.Getenv(envKey99TaxisAPIKey)
-	if apiKey == "" {
-		fmt.Println("No API Key.")
+
+	var (
+		base    http.RoundTripper
+		baseURL string
+		rec     *taxis99.Recorder
+		closers []func()
+		err     error
+	)
+	switch {
+	case *recording:
+		if apiKey == "" {
+			// The fake is served under the path of the real API, so the
+			// cassette is replayed with the default base URL.
+			fake := taxis99test.NewServer("fake-api-key")
+			front := httptest.NewServer(http.StripPrefix("/v2", fake.Config.Handler))
+			closers = append(closers, front.Close, fake.Close)
+			apiKey, baseURL = fake.Key, front.URL+"/v2/"
+		}
+
+		if err := os.MkdirAll(filepath.Dir(cassette), 0755); err != nil {
+			fmt.Printf("Error creating cassette dir: %s.", err.Error())
+			os.Exit(1)
+		}
+		rec, err = taxis99.NewRecorder(cassette, taxis99.ModeRecord, nil)
+		if err != nil {
+			fmt.Printf("Error creating recorder: %s.", err.Error())
+			os.Exit(1)
+		}
+	case apiKey == "":
+		rec, err = taxis99.NewRecorder(cassette, taxis99.ModeReplay, nil)
+		if err != nil {
+			fmt.Printf("Error loading cassette, record it with -record: %s.", err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if rec != nil {
+		src = rand.NewSource(cassetteSeed)
+		base = rec
+	}
+
+	opts := []taxis99.Option{
+		taxis99.WithAPIKey(apiKey),
+		taxis99.WithCompanyID(companyID),
+		taxis99.WithHTTPClient(&http.Client{Transport: base}),
+	}
+	if baseURL != "" {
+		opts = append(opts, taxis99.WithBaseURL(baseURL))
+	}
+	if *logging {
+		opts = append(opts, taxis99.WithLogger(taxis99.NewStdLogger(nil)))
+	}
+
+	tx99, err = taxis99.New(opts...)
+	if err != nil {
+		fmt.Printf("Error creating client: %s.", err.Error())
 		return
 	}
 
-	hc := &http.Client{
-		Transport: &taxis99.Transport{
-			Key:       apiKey,
-			CompanyID: companyID,
-			Base:      loggingHTTPClient().Transport,
-		},
+	code := m.Run()
+
+	if rec != nil && rec.Mode == taxis99.ModeRecord {
+		if err := rec.Save(); err != nil {
+			fmt.Printf("Error saving cassette: %s.", err.Error())
+			code = 1
+		}
+	}
+
+	for _, close := range closers {
+		close()
 	}
-	tx99 = taxis99.NewClient(hc)
 
-	os.Exit(m.Run())
+	os.Exit(code)
 }
 
 func randString(max int, rangeBytes string) string {
@@ -132,15 +162,3 @@ func randString(max int, rangeBytes string) string {
 
 	return *(*string)(unsafe.Pointer(&b))
 }
-
-func loggingHTTPClient() *http.Client {
-	if !*logging {
-		return http.DefaultClient
-	}
-
-	hc := &http.Client{
-		Transport: &transportLogger{http.DefaultTransport},
-	}
-
-	return hc
-}
@@ -0,0 +1,119 @@
+package taxis99
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Tracer starts the spans of the API calls. It mirrors the
+// OpenTelemetry tracer so adapting one takes a few lines.
+// Tracers implementing Propagator also have the trace context
+// injected in the request headers.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a traced API call.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	AddEvent(name string, attrs map[string]interface{})
+	RecordError(err error)
+	End()
+}
+
+// Propagator injects the trace context of ctx, like
+// the W3C traceparent header, in the request headers.
+type Propagator interface {
+	Inject(ctx context.Context, h http.Header)
+}
+
+// Span attributes.
+const (
+	attrOperation  = "taxis99.operation"
+	attrAttempts   = "taxis99.attempts"
+	attrErrorCode  = "taxis99.error.code"
+	attrRequestID  = "taxis99.request_id"
+	attrMethod     = "http.request.method"
+	attrPath       = "url.path"
+	attrStatusCode = "http.response.status_code"
+)
+
+type spanKey struct{}
+
+type propagatorKey struct{}
+
+// Tracing returns a Middleware opening a span per API call,
+// named after the service operation. The span records the HTTP
+// attributes, an event per attempt and the *APIError status.
+func Tracing(t Tracer) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			name := call.Operation
+			if name == "" {
+				name = "taxis99 " + call.Method
+			}
+
+			ctx, span := t.Start(ctx, name)
+			defer span.End()
+
+			ctx = context.WithValue(ctx, spanKey{}, span)
+			if p, ok := t.(Propagator); ok {
+				ctx = context.WithValue(ctx, propagatorKey{}, p)
+			}
+
+			span.SetAttribute(attrOperation, call.Operation)
+			span.SetAttribute(attrMethod, call.Method)
+			span.SetAttribute(attrPath, redactPath(call.Path))
+
+			err := next(ctx, call)
+
+			span.SetAttribute(attrAttempts, call.Attempts)
+			if call.StatusCode != 0 {
+				span.SetAttribute(attrStatusCode, call.StatusCode)
+			}
+
+			if err != nil {
+				var apiErr *APIError
+				if errors.As(err, &apiErr) {
+					span.SetAttribute(attrStatusCode, apiErr.StatusCode)
+					if apiErr.Code != "" {
+						span.SetAttribute(attrErrorCode, apiErr.Code)
+					}
+					if apiErr.RequestID != "" {
+						span.SetAttribute(attrRequestID, apiErr.RequestID)
+					}
+				}
+				span.RecordError(redactError(err))
+			}
+
+			return err
+		}
+	}
+}
+
+// traceAttempt adds the attempt event to the span in the context, if any.
+func traceAttempt(ctx context.Context, attempt int, res *http.Response, err error) {
+	span, ok := ctx.Value(spanKey{}).(Span)
+	if !ok {
+		return
+	}
+
+	attrs := map[string]interface{}{"attempt": attempt}
+	if res != nil {
+		attrs[attrStatusCode] = res.StatusCode
+	}
+	if err != nil {
+		attrs["error"] = redactError(err).Error()
+	}
+
+	span.AddEvent("attempt", attrs)
+}
+
+// injectTraceContext injects the trace context in the
+// request headers, if the context has a Propagator.
+func injectTraceContext(ctx context.Context, h http.Header) {
+	if p, ok := ctx.Value(propagatorKey{}).(Propagator); ok {
+		p.Inject(ctx, h)
+	}
+}
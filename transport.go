@@ -1,7 +1,10 @@
 package taxis99
 
 import (
+	"bytes"
+	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 const (
@@ -28,6 +31,10 @@ type Transport struct {
 
 	// Base is the base RoundTripper to make HTTP request.
 	Base http.RoundTripper
+
+	// Logger logs every request with its header and body, redacting
+	// the API key and personal data. Nothing is logged if it's nil.
+	Logger Logger
 }
 
 // RoundTrip injects the Authorization Header with the key
@@ -47,7 +54,38 @@ func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
 		req.Header.Set(headerCompanyID, cid)
 	}
 
-	return t.base().RoundTrip(req)
+	if t.Logger == nil {
+		return t.base().RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := readBody(req)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	start := time.Now()
+	res, err := t.base().RoundTrip(req)
+
+	e := LogEntry{
+		Method:    req.Method,
+		Path:      redactPath(req.URL.RequestURI()),
+		Latency:   time.Since(start),
+		CompanyID: req.Header.Get(headerCompanyID),
+		Err:       redactError(err),
+		Header:    redactHeader(req.Header),
+		Body:      redactBody(body),
+	}
+	if res != nil {
+		e.StatusCode = res.StatusCode
+	}
+	t.Logger.Log(req.Context(), e)
+
+	return res, err
 }
 
 // base returns the base RoundTripper or the http default transport.
@@ -31,7 +31,7 @@ func TestTransportRoundTrip(t *testing.T) {
 			return nil, nil
 		})
 
-		tr := &Transport{tc.wantAPIKey, tc.wantCompanyID, rt}
+		tr := &Transport{Key: tc.wantAPIKey, CompanyID: tc.wantCompanyID, Base: rt}
 
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 
@@ -69,7 +69,7 @@ func TestTransportRoundTripContext(t *testing.T) {
 			return nil, nil
 		})
 
-		tr := &Transport{"", tc.transportCompanyID, rt}
+		tr := &Transport{CompanyID: tc.transportCompanyID, Base: rt}
 
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 
@@ -91,7 +91,7 @@ func TestTransportRoundTripError(t *testing.T) {
 		return nil, errors.New("Error")
 	})
 
-	tr := &Transport{"", "", rt}
+	tr := &Transport{Base: rt}
 
 	_, err := tr.RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil))
 	if err == nil {